@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"time"
 
@@ -35,12 +38,29 @@ func main() {
 		container.ManagerMetrics,
 		container.ManagerToken,
 		container.ManagerSessionData,
-		container.ManagerWeb)
-
-	go listen(config, route)
+		container.ManagerWeb,
+		container.ManagerDevice,
+		container.ManagerFactor,
+		container.ManagerLockout,
+		container.ManagerAccount,
+		container.ManagerRole,
+		container.ManagerScope,
+		container.ManagerAudit,
+		container.ManagerPassword,
+		container.ManagerNamespace,
+		container.ManagerTakeout,
+		container.ManagerDeletion)
+
+	servers := makeServers(config, route)
+
+	go listen(servers)
 
 	<-config.Signal.Done()
 
+	shutdown(servers)
+
+	container.Close()
+
 	time.Sleep(1 * time.Second)
 
 	for i := 3; i > 0; i-- {
@@ -72,25 +92,101 @@ func addOAPIViewer(config *configuration.Configuration, route *router.Router) *r
 	return route.DocViewer(viewer)
 }
 
-func listen(config *configuration.Configuration, route *router.Router) {
-	err := serve(config, route)
-	if err == nil {
-		return
+const shutdownTimeout = 10 * time.Second
+
+// The headers must arrive promptly, so slow clients cannot hold connections
+// open; whole requests get longer, as takeout archives are uploaded in them.
+const (
+	readHeaderTimeout = 10 * time.Second
+	readTimeout       = 5 * time.Minute
+	idleTimeout       = 2 * time.Minute
+)
+
+type server struct {
+	*http.Server
+	tls  bool
+	cert string
+	key  string
+}
+
+func (s server) serve() error {
+	if s.tls {
+		return s.ListenAndServeTLS(s.cert, s.key)
+	}
+	return s.ListenAndServe()
+}
+
+// makeServers builds the servers of the configured ports. They are held here
+// rather than by the router, so they can be shut down before the repositories
+// are closed and the TLS port can request client certificates.
+func makeServers(config *configuration.Configuration, route *router.Router) []server {
+	servers := make([]server, 0, 2)
+	handler := controller.Handler(route)
+
+	if !config.EnableTLS() || !config.OnlyTLS() {
+		servers = append(servers, server{
+			Server: makeServer(config.Port(), handler),
+		})
+	}
+
+	if config.EnableTLS() {
+		tls := makeServer(config.PortTLS(), handler)
+		if config.ClientCert().Enabled() {
+			tls.TLSConfig = config.ClientCert().TLSConfig()
+		}
+		servers = append(servers, server{
+			Server: tls,
+			tls:    true,
+			cert:   config.CertTLS(),
+			key:    config.KeyTLS(),
+		})
 	}
 
-	log.Errorf("Server exited with error: %v", err)
-	time.Sleep(3 * time.Second)
-	os.Exit(1)
+	return servers
+}
+
+func makeServer(port int, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		IdleTimeout:       idleTimeout,
+	}
 }
 
-func serve(config *configuration.Configuration, route *router.Router) error {
-	if !config.EnableTLS() {
-		return route.Listen(config.Port())
+// listen serves every port and exits the process as soon as any of them
+// fails. A server closed by the shutdown is not a failure.
+func listen(servers []server) {
+	errs := make(chan error, len(servers))
+	for _, v := range servers {
+		log.Messagef("Listening on %s", v.Addr)
+		go func() {
+			errs <- v.serve()
+		}()
 	}
 
-	if config.OnlyTLS() {
-		return route.ListenTLS(config.PortTLS(), config.CertTLS(), config.KeyTLS())
+	for range servers {
+		err := <-errs
+		if err == nil || errors.Is(err, http.ErrServerClosed) {
+			continue
+		}
+
+		log.Errorf("Server exited with error: %v", err)
+		time.Sleep(3 * time.Second)
+		os.Exit(1)
 	}
+}
 
-	return route.ListenWithTLS(config.Port(), config.PortTLS(), config.CertTLS(), config.KeyTLS())
+// shutdown stops accepting connections and waits for the requests in flight,
+// so no change is accepted once the repositories start closing.
+func shutdown(servers []server) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	for _, v := range servers {
+		if err := v.Shutdown(ctx); err != nil {
+			log.Errorf("The server on %s did not shut down cleanly: %v", v.Addr, err)
+		}
+	}
 }
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"strings"
+	"sync"
+
+	core_command "github.com/Rafael24595/go-api-core/src/application/command"
+)
+
+// Handler executes a render command and returns its output. The arguments
+// exclude the command name.
+type Handler func(user string, args []string) string
+
+type definition struct {
+	name    string
+	help    string
+	handler Handler
+}
+
+var (
+	mu       sync.RWMutex
+	commands = make(map[string]definition)
+)
+
+// Register adds a command to the console. Commands not registered here are
+// delegated to the core console.
+func Register(name, help string, handler Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	commands[name] = definition{
+		name:    name,
+		help:    help,
+		handler: handler,
+	}
+}
+
+func Exec(user, cmd string) core_command.CmdResult {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return core_command.Exec(user, cmd)
+	}
+
+	mu.RLock()
+	command, ok := commands[fields[0]]
+	mu.RUnlock()
+
+	if !ok {
+		return core_command.Exec(user, cmd)
+	}
+
+	return core_command.CmdResult{
+		Input:  cmd,
+		Output: command.handler(user, fields[1:]),
+	}
+}
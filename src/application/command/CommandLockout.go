@@ -0,0 +1,77 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Rafael24595/go-api-render/src/application/manager"
+)
+
+const lockoutUsage = `Usage:
+  lockout list                    Lists the tracked users and addresses
+  lockout clear user <username>   Clears the lockout of a user
+  lockout clear address <address> Clears the lockout of a client address
+  lockout clear all               Clears every lockout`
+
+func RegisterLockout(managerLockout *manager.ManagerLockout) {
+	Register("lockout", "Lists and clears the login lockouts", func(user string, args []string) string {
+		return lockout(managerLockout, args)
+	})
+}
+
+func lockout(managerLockout *manager.ManagerLockout, args []string) string {
+	if len(args) == 0 {
+		return lockoutUsage
+	}
+
+	switch args[0] {
+	case "list":
+		return lockoutList(managerLockout)
+	case "clear":
+		return lockoutClear(managerLockout, args[1:])
+	default:
+		return lockoutUsage
+	}
+}
+
+func lockoutList(managerLockout *manager.ManagerLockout) string {
+	lockouts := managerLockout.FindAll()
+	if len(lockouts) == 0 {
+		return "There are no failed logins tracked."
+	}
+
+	now := time.Now()
+
+	lines := make([]string, len(lockouts))
+	for i, v := range lockouts {
+		status := "active"
+		if until := time.UnixMilli(v.Until); until.After(now) {
+			status = fmt.Sprintf("locked for %s", until.Sub(now).Round(time.Second))
+		}
+		lines[i] = fmt.Sprintf("%s %q: %d failures, %s", v.Kind, v.Subject, v.Failures, status)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func lockoutClear(managerLockout *manager.ManagerLockout, args []string) string {
+	if len(args) == 1 && args[0] == "all" {
+		return fmt.Sprintf("%d lockouts cleared.", managerLockout.ClearAll())
+	}
+
+	if len(args) != 2 {
+		return lockoutUsage
+	}
+
+	kind := manager.LockoutKind(args[0])
+	if kind != manager.LOCKOUT_USER && kind != manager.LOCKOUT_ADDRESS {
+		return lockoutUsage
+	}
+
+	if !managerLockout.Clear(kind, args[1]) {
+		return fmt.Sprintf("The %s %q is not tracked.", kind, args[1])
+	}
+
+	return fmt.Sprintf("The lockout of the %s %q has been cleared.", kind, args[1])
+}
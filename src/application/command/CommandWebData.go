@@ -0,0 +1,45 @@
+package command
+
+import (
+	"fmt"
+
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/domain/web"
+)
+
+const webDataUsage = `Usage:
+  webdata migrate   Imports the web data of the file storage into the database`
+
+// WebDataImporter stores web data keeping its identifiers and dates.
+type WebDataImporter interface {
+	Import(items []web.WebData) (int, int, error)
+}
+
+func RegisterWebDataMigration(source core_repository.IFileManager[web.WebData], target WebDataImporter) {
+	Register("webdata", "Migrates the web data between storages", func(user string, args []string) string {
+		return webData(source, target, args)
+	})
+}
+
+func webData(source core_repository.IFileManager[web.WebData], target WebDataImporter, args []string) string {
+	if len(args) != 1 || args[0] != "migrate" {
+		return webDataUsage
+	}
+
+	items, err := source.Read()
+	if err != nil {
+		return fmt.Sprintf("The web data cannot be read: %s", err.Error())
+	}
+
+	values := make([]web.WebData, 0, len(items))
+	for _, v := range items {
+		values = append(values, v)
+	}
+
+	imported, skipped, err := target.Import(values)
+	if err != nil {
+		return fmt.Sprintf("The web data cannot be imported: %s", err.Error())
+	}
+
+	return fmt.Sprintf("%d web data imported, %d skipped as already present.", imported, skipped)
+}
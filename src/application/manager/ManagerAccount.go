@@ -0,0 +1,234 @@
+package manager
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	domain_session "github.com/Rafael24595/go-api-core/src/domain/session"
+	"github.com/Rafael24595/go-api-render/src/domain/account"
+)
+
+// ManagerAccount keeps the account state the core sessions do not model: the
+// users known by the application, whether they are disabled and the roles
+// granted or revoked by admins on top of the roles assigned on creation.
+type ManagerAccount struct {
+	mu      sync.Mutex
+	account account.Repository
+}
+
+func NewManagerAccount(account account.Repository) *ManagerAccount {
+	return &ManagerAccount{
+		account: account,
+	}
+}
+
+func (m *ManagerAccount) FindAll() []account.Account {
+	return m.account.FindAll()
+}
+
+func (m *ManagerAccount) Find(owner string) (*account.Account, bool) {
+	result, ok := m.account.FindByOwner(owner)
+	if !ok || result == nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// FindByIdentity returns the account linked to the given user of an identity
+// provider.
+func (m *ManagerAccount) FindByIdentity(issuer, subject string) (*account.Account, bool) {
+	result, ok := m.account.FindByIdentity(issuer, subject)
+	if !ok || result == nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// Link records a user provisioned from an identity provider, bound to its
+// issuer and subject.
+func (m *ManagerAccount) Link(owner, issuer, subject string) *account.Account {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result, ok := m.Find(owner)
+	if !ok {
+		result = account.NewAccount(owner, account.PROVIDER_OIDC)
+	}
+
+	result.Provider = account.PROVIDER_OIDC
+	result.Issuer = issuer
+	result.Subject = subject
+
+	return m.account.Resolve(owner, result)
+}
+
+// Register records the user if it is not known yet. Users created before the
+// registry existed are recorded on their next login.
+func (m *ManagerAccount) Register(owner, provider string) *account.Account {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if result, ok := m.Find(owner); ok {
+		return result
+	}
+
+	return m.account.Resolve(owner, account.NewAccount(owner, provider))
+}
+
+func (m *ManagerAccount) IsDisabled(owner string) bool {
+	result, ok := m.Find(owner)
+	return ok && result.IsDisabled()
+}
+
+// Resolve returns a copy of the session with the effective roles of the user.
+func (m *ManagerAccount) Resolve(session *domain_session.Session) *domain_session.Session {
+	if session == nil {
+		return nil
+	}
+
+	result := *session
+	result.Roles = m.Roles(session.Username, session.Roles)
+
+	return &result
+}
+
+func (m *ManagerAccount) Roles(owner string, roles []domain_session.Role) []domain_session.Role {
+	result, ok := m.Find(owner)
+	if !ok {
+		return roles
+	}
+
+	raw := make([]string, len(roles))
+	for i, v := range roles {
+		raw[i] = string(v)
+	}
+
+	effective := result.Roles(raw)
+
+	resolved := make([]domain_session.Role, len(effective))
+	for i, v := range effective {
+		resolved[i] = domain_session.Role(v)
+	}
+
+	return resolved
+}
+
+func (m *ManagerAccount) Grant(owner string, role domain_session.Role) *account.Account {
+	return m.update(owner, func(a *account.Account) {
+		a.Revokes = slices.DeleteFunc(a.Revokes, func(v string) bool {
+			return v == string(role)
+		})
+		if !slices.Contains(a.Grants, string(role)) {
+			a.Grants = append(a.Grants, string(role))
+		}
+	})
+}
+
+func (m *ManagerAccount) Revoke(owner string, role domain_session.Role) *account.Account {
+	return m.update(owner, func(a *account.Account) {
+		a.Grants = slices.DeleteFunc(a.Grants, func(v string) bool {
+			return v == string(role)
+		})
+		if !slices.Contains(a.Revokes, string(role)) {
+			a.Revokes = append(a.Revokes, string(role))
+		}
+	})
+}
+
+func (m *ManagerAccount) Disable(owner string) *account.Account {
+	return m.update(owner, func(a *account.Account) {
+		if !a.IsDisabled() {
+			a.Disabled = time.Now().UnixMilli()
+		}
+		a.Suspended = false
+	})
+}
+
+// Enable also cancels a pending deletion of the account.
+func (m *ManagerAccount) Enable(owner string) *account.Account {
+	return m.update(owner, func(a *account.Account) {
+		a.Disabled = 0
+		a.Deletion = 0
+		a.Suspended = false
+	})
+}
+
+// ScheduleDeletion disables the account until its data is purged at the
+// given time.
+func (m *ManagerAccount) ScheduleDeletion(owner string, purge int64) *account.Account {
+	return m.update(owner, func(a *account.Account) {
+		if !a.IsDisabled() {
+			a.Disabled = time.Now().UnixMilli()
+			a.Suspended = true
+		}
+		a.Deletion = purge
+	})
+}
+
+// CancelDeletion drops the pending deletion of the account. The account is
+// enabled again only if the deletion was what disabled it.
+func (m *ManagerAccount) CancelDeletion(owner string) *account.Account {
+	return m.update(owner, func(a *account.Account) {
+		if a.Suspended {
+			a.Disabled = 0
+		}
+		a.Deletion = 0
+		a.Suspended = false
+	})
+}
+
+// ClearDeletion drops the pending deletion mark and leaves the account
+// disabled.
+func (m *ManagerAccount) ClearDeletion(owner string) *account.Account {
+	return m.update(owner, func(a *account.Account) {
+		a.Deletion = 0
+		a.Suspended = false
+	})
+}
+
+// FindDueDeletions returns the accounts whose data must be purged by now.
+func (m *ManagerAccount) FindDueDeletions(now int64) []account.Account {
+	result := make([]account.Account, 0)
+	for _, v := range m.FindAll() {
+		if v.IsPendingDeletion() && v.Deletion <= now {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// RecordPassword keeps the digest of the new password of the user, dropping
+// the oldest ones beyond the limit.
+func (m *ManagerAccount) RecordPassword(owner, digest string, limit int) *account.Account {
+	return m.update(owner, func(a *account.Account) {
+		a.Passwords = append([]string{digest}, a.Passwords...)
+		if len(a.Passwords) > limit {
+			a.Passwords = a.Passwords[:limit]
+		}
+		a.Changed = time.Now().UnixMilli()
+	})
+}
+
+func (m *ManagerAccount) Delete(owner string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if result, ok := m.Find(owner); ok {
+		m.account.Delete(result)
+	}
+}
+
+func (m *ManagerAccount) update(owner string, change func(*account.Account)) *account.Account {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result, ok := m.Find(owner)
+	if !ok {
+		result = account.NewAccount(owner, account.PROVIDER_LOCAL)
+	}
+
+	change(result)
+
+	return m.account.Resolve(owner, result)
+}
@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"slices"
+
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+)
+
+// ManagerAudit keeps the append-only trail of the security events.
+type ManagerAudit struct {
+	audit audit.Repository
+}
+
+func NewManagerAudit(audit audit.Repository) *ManagerAudit {
+	return &ManagerAudit{
+		audit: audit,
+	}
+}
+
+// FindAll returns the events matching the filter, oldest first.
+func (m *ManagerAudit) FindAll(filter audit.Filter) []audit.Event {
+	result := make([]audit.Event, 0)
+	for _, v := range m.audit.FindAll() {
+		if filter.Matches(v) {
+			result = append(result, v)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b audit.Event) int {
+		if a.Timestamp < b.Timestamp {
+			return -1
+		}
+		if a.Timestamp > b.Timestamp {
+			return 1
+		}
+		return 0
+	})
+
+	return result
+}
+
+func (m *ManagerAudit) Record(event *audit.Event) *audit.Event {
+	return m.audit.Insert(event)
+}
+
+// Close releases the repository.
+func (m *ManagerAudit) Close() error {
+	return m.audit.Close()
+}
@@ -0,0 +1,390 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	core_manager "github.com/Rafael24595/go-api-core/src/application/manager"
+	"github.com/Rafael24595/go-api-core/src/application/session"
+	"github.com/Rafael24595/go-api-core/src/domain/action"
+	domain_session "github.com/Rafael24595/go-api-core/src/domain/session"
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-log/log"
+)
+
+const DELETION_CATEGORY = "DELETION"
+
+const deletionInterval = time.Minute
+
+// DeletionReport tells what the deletion of a user removed. A scheduled
+// deletion reports the time of the purge and nothing removed yet.
+type DeletionReport struct {
+	Owner       string `json:"owner"`
+	Scheduled   int64  `json:"scheduled"`
+	Purged      bool   `json:"purged"`
+	Collections int    `json:"collections"`
+	Requests    int    `json:"requests"`
+	Historic    int    `json:"historic"`
+	EndPoints   int    `json:"end_points"`
+	Metrics     int    `json:"metrics"`
+	Tokens      int    `json:"tokens"`
+	WebData     bool   `json:"web_data"`
+	Revisions   int    `json:"revisions"`
+	Devices     int    `json:"devices"`
+	Factor      bool   `json:"factor"`
+	Account     bool   `json:"account"`
+}
+
+// Summary describes the report in a single line, for the audit log.
+func (r DeletionReport) Summary() string {
+	if !r.Purged {
+		return fmt.Sprintf("scheduled=%d", r.Scheduled)
+	}
+	return fmt.Sprintf("collections=%d requests=%d historic=%d end_points=%d metrics=%d tokens=%d revisions=%d devices=%d",
+		r.Collections, r.Requests, r.Historic, r.EndPoints, r.Metrics, r.Tokens, r.Revisions, r.Devices)
+}
+
+// DeletionIncomplete reports a purge that could not remove every artifact of
+// the user. The core offers no operation to remove a context nor the
+// collections it creates for a session, so those are left behind, emptied.
+// The audit log is append-only and keeps the events of the user on purpose.
+type DeletionIncomplete struct {
+	Message  string          `json:"message"`
+	Contexts int             `json:"contexts"`
+	Sessions int             `json:"session_collections"`
+	Report   *DeletionReport `json:"report"`
+}
+
+func (e *DeletionIncomplete) Error() string {
+	return e.Message
+}
+
+func newDeletionIncomplete(report *DeletionReport, contexts, sessions int) *DeletionIncomplete {
+	return &DeletionIncomplete{
+		Message:  fmt.Sprintf("the data of %q has been purged, but %d contexts and %d session collections cannot be removed", report.Owner, contexts, sessions),
+		Contexts: contexts,
+		Sessions: sessions,
+		Report:   report,
+	}
+}
+
+// ManagerDeletion removes every artifact a user owns across the managers,
+// either at once or after a grace period during which the account stays
+// disabled.
+type ManagerDeletion struct {
+	mu                 sync.Mutex
+	once               sync.Once
+	deletion           configuration.Deletion
+	managerCollection  *core_manager.ManagerCollection
+	managerGroup       *core_manager.ManagerGroup
+	managerHistoric    *core_manager.ManagerHistoric
+	managerEndPoint    *core_manager.ManagerEndPoint
+	managerMetrics     *core_manager.ManagerMetrics
+	managerToken       *core_manager.ManagerToken
+	managerSessionData *session.ManagerSessionData
+	managerWeb         *ManagerWeb
+	managerDevice      *ManagerDevice
+	managerFactor      *ManagerFactor
+	managerLockout     *ManagerLockout
+	managerAccount     *ManagerAccount
+	managerRole        *ManagerRole
+	managerScope       *ManagerScope
+	managerAudit       *ManagerAudit
+}
+
+func NewManagerDeletion(
+	deletion configuration.Deletion,
+	managerCollection *core_manager.ManagerCollection,
+	managerGroup *core_manager.ManagerGroup,
+	managerHistoric *core_manager.ManagerHistoric,
+	managerEndPoint *core_manager.ManagerEndPoint,
+	managerMetrics *core_manager.ManagerMetrics,
+	managerToken *core_manager.ManagerToken,
+	managerSessionData *session.ManagerSessionData,
+	managerWeb *ManagerWeb,
+	managerDevice *ManagerDevice,
+	managerFactor *ManagerFactor,
+	managerLockout *ManagerLockout,
+	managerAccount *ManagerAccount,
+	managerRole *ManagerRole,
+	managerScope *ManagerScope,
+	managerAudit *ManagerAudit,
+) *ManagerDeletion {
+	instance := &ManagerDeletion{
+		deletion:           deletion,
+		managerCollection:  managerCollection,
+		managerGroup:       managerGroup,
+		managerHistoric:    managerHistoric,
+		managerEndPoint:    managerEndPoint,
+		managerMetrics:     managerMetrics,
+		managerToken:       managerToken,
+		managerSessionData: managerSessionData,
+		managerWeb:         managerWeb,
+		managerDevice:      managerDevice,
+		managerFactor:      managerFactor,
+		managerLockout:     managerLockout,
+		managerAccount:     managerAccount,
+		managerRole:        managerRole,
+		managerScope:       managerScope,
+		managerAudit:       managerAudit,
+	}
+
+	go instance.watch()
+
+	return instance
+}
+
+// Delete removes the target user on behalf of the actor, who may be the user
+// itself. With a grace period the account is disabled, its devices revoked
+// and the purge scheduled; otherwise the session and the data are removed
+// at once. A purge that leaves artifacts behind returns its report along with
+// a *DeletionIncomplete error.
+func (m *ManagerDeletion) Delete(actor, target *domain_session.Session) (*DeletionReport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.deletion.Enabled() {
+		if _, err := session.InstanceManagerSession().Delete(actor, target); err != nil {
+			return nil, err
+		}
+		return m.purge(target.Username)
+	}
+
+	if err := m.authorize(actor, target); err != nil {
+		return nil, err
+	}
+
+	purge := time.Now().Add(m.deletion.Grace).UnixMilli()
+
+	m.managerAccount.ScheduleDeletion(target.Username, purge)
+	m.managerDevice.RevokeAll(target.Username)
+
+	return &DeletionReport{
+		Owner:     target.Username,
+		Scheduled: purge,
+	}, nil
+}
+
+// authorize checks the actor may delete the target before the deletion is
+// scheduled, as the core does when the session is removed at once. Users may
+// delete themselves; removing anyone else takes the permission to manage
+// users, and neither the anonymous user nor an admin can be removed by others.
+func (m *ManagerDeletion) authorize(actor, target *domain_session.Session) error {
+	if target.Username == action.ANONYMOUS_OWNER {
+		return errors.New("the anonymous user cannot be deleted")
+	}
+
+	if actor.Username == target.Username {
+		return nil
+	}
+
+	roles := m.managerAccount.Roles(actor.Username, actor.Roles)
+	if !m.managerRole.IsGranted(roles, role.PERMISSION_USERS_MANAGE) {
+		return fmt.Errorf("the %q permission is required", role.PERMISSION_USERS_MANAGE)
+	}
+
+	if m.managerAccount.Resolve(target).HasRole(domain_session.ROLE_ADMIN) {
+		return errors.New("an admin cannot be deleted by another user")
+	}
+
+	return nil
+}
+
+// Cancel drops the pending deletion of an account. An account an admin had
+// disabled before stays disabled.
+func (m *ManagerDeletion) Cancel(owner string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, ok := m.managerAccount.Find(owner)
+	if !ok || !account.IsPendingDeletion() {
+		return false
+	}
+
+	m.managerAccount.CancelDeletion(owner)
+
+	return true
+}
+
+func (m *ManagerDeletion) watch() {
+	m.once.Do(func() {
+		if !m.deletion.Enabled() {
+			return
+		}
+
+		conf := configuration.Instance()
+
+		ticker := time.NewTicker(deletionInterval)
+		defer ticker.Stop()
+
+		for {
+			m.purgeDue()
+
+			select {
+			case <-conf.Signal.Done():
+				log.Customf(DELETION_CATEGORY, "Purge stopped: global shutdown signal received.")
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// purgeDue purges the users whose grace period is over. The session of the
+// user is removed as the user itself would do. Should that fail, the purge is
+// not retried: the account stays disabled and the failure is audited.
+func (m *ManagerDeletion) purgeDue() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessions := session.InstanceManagerSession()
+
+	for _, v := range m.managerAccount.FindDueDeletions(time.Now().UnixMilli()) {
+		if user, ok := sessions.Find(v.Owner); ok {
+			if _, err := sessions.Delete(user, user); err != nil {
+				log.Custome(DELETION_CATEGORY, err)
+				m.managerAccount.ClearDeletion(v.Owner)
+				m.managerAudit.Record(audit.NewEvent(audit.KIND_USER_PURGE, v.Owner, v.Owner, fmt.Sprintf("failed: %s", err.Error())))
+				continue
+			}
+		}
+
+		report, err := m.purge(v.Owner)
+		if err != nil {
+			log.Custome(DELETION_CATEGORY, err)
+			m.managerAudit.Record(audit.NewEvent(audit.KIND_USER_PURGE, v.Owner, v.Owner, fmt.Sprintf("incomplete: %s; %s", err.Error(), report.Summary())))
+			continue
+		}
+
+		log.Customf(DELETION_CATEGORY, "The data of the user %q has been purged.", v.Owner)
+
+		m.managerAudit.Record(audit.NewEvent(audit.KIND_USER_PURGE, v.Owner, v.Owner, report.Summary()))
+	}
+}
+
+func (m *ManagerDeletion) purge(owner string) (*DeletionReport, error) {
+	report := &DeletionReport{
+		Owner:  owner,
+		Purged: true,
+	}
+
+	contexts := make(map[string]bool)
+
+	roots := 0
+	roots += m.purgeCollections(owner, report, contexts)
+	roots += m.purgeRequests(owner, report, contexts)
+	roots += m.purgeHistoric(owner, report, contexts)
+
+	for _, v := range m.managerEndPoint.FindAll(owner) {
+		if metrics := m.managerMetrics.Delete(owner, &v); metrics != nil {
+			report.Metrics++
+		}
+		if endPoint := m.managerEndPoint.Delete(owner, v.Id); endPoint != nil {
+			report.EndPoints++
+		}
+	}
+
+	for _, v := range m.managerToken.FindAll(owner) {
+		if _, ok := m.managerToken.DeleteById(owner, v.Id); ok {
+			m.managerScope.Delete(v.Id)
+			report.Tokens++
+		}
+	}
+
+	report.Revisions = len(m.managerWeb.History(owner))
+	if m.managerWeb.Exists(owner) {
+		if _, err := m.managerWeb.Delete(owner); err != nil {
+			log.Custome(DELETION_CATEGORY, err)
+		} else {
+			report.WebData = true
+		}
+	}
+
+	report.Devices = len(m.managerDevice.RevokeAll(owner))
+	report.Factor = m.managerFactor.Reset(owner)
+	m.managerLockout.Clear(LOCKOUT_USER, owner)
+
+	_, report.Account = m.managerAccount.Find(owner)
+	m.managerAccount.Delete(owner)
+
+	delete(contexts, "")
+
+	if len(contexts) > 0 || roots > 0 {
+		return report, newDeletionIncomplete(report, len(contexts), roots)
+	}
+
+	return report, nil
+}
+
+func (m *ManagerDeletion) purgeCollections(owner string, report *DeletionReport, contexts map[string]bool) int {
+	group, err := m.managerSessionData.FindCollections(owner)
+	if err != nil {
+		log.Custome(DELETION_CATEGORY, err)
+		return 0
+	}
+
+	for _, v := range group.Nodes {
+		if dto, ok := m.managerCollection.FindDto(owner, v.Item); ok {
+			contexts[dto.Context.Id] = true
+			for _, n := range dto.Nodes {
+				if _, request, _ := m.managerCollection.DeleteRequestFromCollectionById(owner, v.Item, n.Request.Id); request != nil {
+					report.Requests++
+				}
+			}
+		}
+
+		result, collection := m.managerGroup.DeleteCollection(owner, group, v.Item)
+		if result != nil {
+			group = result
+		}
+		if collection != nil {
+			report.Collections++
+		}
+	}
+
+	return 1
+}
+
+func (m *ManagerDeletion) purgeRequests(owner string, report *DeletionReport, contexts map[string]bool) int {
+	persistent, err := m.managerSessionData.FindPersistent(owner)
+	if err != nil {
+		log.Custome(DELETION_CATEGORY, err)
+		return 0
+	}
+
+	contexts[persistent.Context] = true
+
+	for _, v := range m.managerCollection.FindLiteRequestNodes(owner, persistent) {
+		if _, request, _ := m.managerCollection.DeleteRequestFromCollectionById(owner, persistent.Id, v.Request.Id); request != nil {
+			report.Requests++
+		}
+	}
+
+	return 1
+}
+
+func (m *ManagerDeletion) purgeHistoric(owner string, report *DeletionReport, contexts map[string]bool) int {
+	transient, err := m.managerSessionData.FindTransient(owner)
+	if err != nil {
+		log.Custome(DELETION_CATEGORY, err)
+		return 0
+	}
+
+	contexts[transient.Context] = true
+
+	for _, v := range m.managerHistoric.FindLite(owner, transient) {
+		result, request, _ := m.managerHistoric.Delete(owner, transient, v.Request.Id)
+		if result != nil {
+			transient = result
+		}
+		if request != nil {
+			report.Historic++
+		}
+	}
+
+	return 1
+}
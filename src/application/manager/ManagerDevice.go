@@ -0,0 +1,172 @@
+package manager
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	auth "github.com/Rafael24595/go-api-render/src/commons/auth/Jwt.go"
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/domain/device"
+	"github.com/google/uuid"
+)
+
+// The activity of a session is only stored once per interval, as every
+// authenticated request reports it.
+const touchInterval = time.Minute
+
+type ManagerDevice struct {
+	mu     sync.Mutex
+	device device.Repository
+	policy configuration.Session
+}
+
+func NewManagerDevice(device device.Repository, policy configuration.Session) *ManagerDevice {
+	instance := &ManagerDevice{
+		device: device,
+		policy: policy,
+	}
+
+	instance.restoreDenylist()
+
+	return instance
+}
+
+// restoreDenylist denies again the sessions revoked recently enough to still
+// hold valid access tokens, since the denylist does not survive a restart.
+func (m *ManagerDevice) restoreDenylist() {
+	limit := time.Now().Add(-m.policy.Access).UnixMilli()
+	for _, v := range m.device.FindAll() {
+		if v.IsRevoked() && v.Revoked > limit {
+			expires := time.UnixMilli(v.Revoked).Add(m.policy.Access)
+			auth.Deny(expires, v.Id, v.Access)
+		}
+	}
+}
+
+func (m *ManagerDevice) Find(owner, id string) (*device.Device, bool) {
+	result, ok := m.device.Find(id)
+	if !ok || result == nil || result.Owner != owner {
+		return nil, false
+	}
+	return result, true
+}
+
+func (m *ManagerDevice) FindByOwner(owner string) []device.Device {
+	devices := make([]device.Device, 0)
+	for _, v := range m.device.FindByOwner(owner) {
+		if !v.IsRevoked() {
+			devices = append(devices, v)
+		}
+	}
+
+	slices.SortFunc(devices, func(a, b device.Device) int {
+		return cmp.Compare(b.LastSeen, a.LastSeen)
+	})
+
+	return devices
+}
+
+// Open starts a new session family for the device that performed the login.
+// Remembered sessions follow the longer lifetimes of the remember policy.
+func (m *ManagerDevice) Open(owner, agent, address string, remember bool) *device.Device {
+	m.purge(owner)
+
+	result := device.NewDevice(owner, agent, address, remember)
+	result.Refresh = uuid.NewString()
+	result.Access = uuid.NewString()
+
+	return m.device.Resolve(owner, result)
+}
+
+// Rotate replaces the token identifiers of the session family, so the
+// previously issued refresh token can no longer be exchanged.
+func (m *ManagerDevice) Rotate(owner string, result *device.Device, agent, address string) *device.Device {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result.Refresh = uuid.NewString()
+	result.Access = uuid.NewString()
+	result.Agent = agent
+	result.Address = address
+	result.LastSeen = time.Now().UnixMilli()
+	return m.device.Resolve(owner, result)
+}
+
+func (m *ManagerDevice) Revoke(owner string, result *device.Device) *device.Device {
+	if result.IsRevoked() {
+		return result
+	}
+	result.Revoked = time.Now().UnixMilli()
+	auth.Deny(time.Now().Add(m.policy.Access), result.Id, result.Access)
+	return m.device.Resolve(owner, result)
+}
+
+func (m *ManagerDevice) RevokeAll(owner string, except ...string) []device.Device {
+	revoked := make([]device.Device, 0)
+	for _, v := range m.FindByOwner(owner) {
+		if slices.Contains(except, v.Id) {
+			continue
+		}
+		revoked = append(revoked, *m.Revoke(owner, &v))
+	}
+	return revoked
+}
+
+// Touch records the activity of the session, sliding its idle expiration.
+// The session is read again so a concurrent rotation is not overwritten.
+func (m *ManagerDevice) Touch(owner string, result *device.Device) *device.Device {
+	now := time.Now()
+	if now.Sub(time.UnixMilli(result.LastSeen)) < touchInterval {
+		return result
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.Find(owner, result.Id)
+	if !ok || current.IsRevoked() {
+		return result
+	}
+
+	current.LastSeen = now.UnixMilli()
+	return m.device.Resolve(owner, current)
+}
+
+// IsExpired tells whether the session has been idle for too long or has
+// reached its absolute lifetime.
+func (m *ManagerDevice) IsExpired(result *device.Device) bool {
+	return m.Remaining(result) <= 0
+}
+
+// Remaining returns the time left before the session expires, counting from
+// its last activity and bounded by its absolute lifetime.
+func (m *ManagerDevice) Remaining(result *device.Device) time.Duration {
+	now := time.Now()
+	policy := m.policy.Policy(result.Remember)
+
+	remaining := time.UnixMilli(result.LastSeen).Add(policy.Idle).Sub(now)
+	if policy.Absolute > 0 {
+		remaining = min(remaining, time.UnixMilli(result.Timestamp).Add(policy.Absolute).Sub(now))
+	}
+
+	return remaining
+}
+
+// Lifetimes returns the lifetimes of the access and refresh tokens issued
+// for the session, none of them outliving it.
+func (m *ManagerDevice) Lifetimes(result *device.Device) (time.Duration, time.Duration) {
+	remaining := max(m.Remaining(result), 0)
+	return min(m.policy.Access, remaining), remaining
+}
+
+func (m *ManagerDevice) purge(owner string) {
+	idle := max(m.policy.Idle, m.policy.Remember.Idle)
+	limit := time.Now().Add(-idle).UnixMilli()
+	for _, v := range m.device.FindByOwner(owner) {
+		if (v.IsRevoked() && v.Revoked < limit) || v.LastSeen < limit {
+			m.device.Delete(&v)
+		}
+	}
+}
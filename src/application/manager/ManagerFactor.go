@@ -0,0 +1,323 @@
+package manager
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	domain_session "github.com/Rafael24595/go-api-core/src/domain/session"
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/commons/totp"
+	"github.com/Rafael24595/go-api-render/src/domain/factor"
+	"github.com/Rafael24595/go-api-render/src/domain/setting"
+)
+
+const ChallengeLifetime = 5 * time.Minute
+
+const challengeAttempts = 5
+const recoveryCodes = 10
+const recoverySize = 5
+
+type challenge struct {
+	owner    string
+	expires  time.Time
+	attempts int
+}
+
+type ManagerFactor struct {
+	mu         sync.Mutex
+	factor     factor.Repository
+	setting    *ManagerSetting
+	challenges map[string]challenge
+}
+
+func NewManagerFactor(factor factor.Repository, setting *ManagerSetting) *ManagerFactor {
+	return &ManagerFactor{
+		factor:     factor,
+		setting:    setting,
+		challenges: make(map[string]challenge),
+	}
+}
+
+func (m *ManagerFactor) Find(owner string) (*factor.Factor, bool) {
+	result, ok := m.factor.FindByOwner(owner)
+	if !ok || result == nil {
+		return nil, false
+	}
+	return result, true
+}
+
+func (m *ManagerFactor) IsEnabled(owner string) bool {
+	result, ok := m.Find(owner)
+	return ok && result.IsEnabled()
+}
+
+func (m *ManagerFactor) AdminsRequired() bool {
+	def := configuration.Instance().TotpAdmins()
+	return m.setting.FindBool(setting.AUTH_2FA_ADMINS, def)
+}
+
+func (m *ManagerFactor) RequireAdmins(actor string, value bool) bool {
+	m.setting.ResolveBool(actor, setting.AUTH_2FA_ADMINS, value)
+	return value
+}
+
+// IsRequired reports whether the user must enroll a second factor before
+// accessing the protected routes.
+func (m *ManagerFactor) IsRequired(session *domain_session.Session) bool {
+	return session.HasRole(domain_session.ROLE_ADMIN) && m.AdminsRequired()
+}
+
+// Enroll generates a new secret for the user. The factor remains pending
+// until a code generated from it is confirmed.
+func (m *ManagerFactor) Enroll(owner string) (*factor.Factor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result, ok := m.Find(owner)
+	if ok && result.IsEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		result = factor.NewFactor(owner, secret)
+	}
+
+	result.Secret = secret
+	result.Recovery = make([]string, 0)
+	result.LastStep = 0
+
+	return m.factor.Resolve(owner, result), nil
+}
+
+// Confirm enables the pending factor and returns the recovery codes, which
+// are only stored hashed and cannot be retrieved again.
+func (m *ManagerFactor) Confirm(owner, code string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result, ok := m.Find(owner)
+	if !ok {
+		return nil, errors.New("two-factor authentication enrollment has not been started")
+	}
+
+	if result.IsEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	step, ok := totp.Validate(result.Secret, code, time.Now())
+	if !ok {
+		return nil, errors.New("the verification code is not valid")
+	}
+
+	codes, hashes, err := generateRecovery()
+	if err != nil {
+		return nil, err
+	}
+
+	result.LastStep = step
+	result.Recovery = hashes
+	result.Enabled = time.Now().UnixMilli()
+
+	m.factor.Resolve(owner, result)
+
+	return codes, nil
+}
+
+// Verify checks a TOTP code or consumes a recovery code of an enabled factor.
+func (m *ManagerFactor) Verify(owner, code string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.verify(owner, code)
+}
+
+func (m *ManagerFactor) verify(owner, code string) bool {
+	result, ok := m.Find(owner)
+	if !ok || !result.IsEnabled() {
+		return false
+	}
+
+	if step, ok := totp.Validate(result.Secret, code, time.Now()); ok {
+		if step <= result.LastStep {
+			return false
+		}
+		result.LastStep = step
+		m.factor.Resolve(owner, result)
+		return true
+	}
+
+	hash := hashRecovery(code)
+	index := slices.IndexFunc(result.Recovery, func(v string) bool {
+		return subtle.ConstantTimeCompare([]byte(v), []byte(hash)) == 1
+	})
+	if index == -1 {
+		return false
+	}
+
+	result.Recovery = slices.Delete(result.Recovery, index, index+1)
+	m.factor.Resolve(owner, result)
+
+	return true
+}
+
+// Regenerate replaces the recovery codes after verifying the given code.
+func (m *ManagerFactor) Regenerate(owner, code string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.verify(owner, code) {
+		return nil, errors.New("the verification code is not valid")
+	}
+
+	result, _ := m.Find(owner)
+
+	codes, hashes, err := generateRecovery()
+	if err != nil {
+		return nil, err
+	}
+
+	result.Recovery = hashes
+	m.factor.Resolve(owner, result)
+
+	return codes, nil
+}
+
+// Disable removes the factor after verifying the given code.
+func (m *ManagerFactor) Disable(owner, code string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.verify(owner, code) {
+		return errors.New("the verification code is not valid")
+	}
+
+	result, _ := m.Find(owner)
+	m.factor.Delete(result)
+
+	return nil
+}
+
+// Reset removes the factor of a user without verification, for admins
+// assisting users that lost their device and recovery codes.
+func (m *ManagerFactor) Reset(owner string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result, ok := m.Find(owner)
+	if !ok {
+		return false
+	}
+
+	m.factor.Delete(result)
+
+	return true
+}
+
+// Challenge opens the second step of a login whose password has already been
+// verified, returning the identifier the client must answer with a code.
+func (m *ManagerFactor) Challenge(owner string) (string, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.purge()
+
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", time.Time{}, err
+	}
+
+	id := hex.EncodeToString(buffer)
+	expires := time.Now().Add(ChallengeLifetime)
+
+	m.challenges[id] = challenge{
+		owner:   owner,
+		expires: expires,
+	}
+
+	return id, expires, nil
+}
+
+// Owner returns the user a pending login challenge was issued for.
+func (m *ManagerFactor) Owner(id string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.challenges[id]
+	if !ok || time.Now().After(current.expires) {
+		return "", false
+	}
+
+	return current.owner, true
+}
+
+// Answer resolves a login challenge and returns the user it was issued for.
+// A challenge is discarded once answered or after too many failed attempts.
+func (m *ManagerFactor) Answer(id, code string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.challenges[id]
+	if !ok || time.Now().After(current.expires) {
+		delete(m.challenges, id)
+		return "", errors.New("the login challenge is unknown or has expired")
+	}
+
+	if !m.verify(current.owner, code) {
+		current.attempts++
+		if current.attempts >= challengeAttempts {
+			delete(m.challenges, id)
+		} else {
+			m.challenges[id] = current
+		}
+		return "", errors.New("the verification code is not valid")
+	}
+
+	delete(m.challenges, id)
+
+	return current.owner, nil
+}
+
+func (m *ManagerFactor) purge() {
+	now := time.Now()
+	for k, v := range m.challenges {
+		if now.After(v.expires) {
+			delete(m.challenges, k)
+		}
+	}
+}
+
+func generateRecovery() ([]string, []string, error) {
+	codes := make([]string, recoveryCodes)
+	hashes := make([]string, recoveryCodes)
+
+	for i := range recoveryCodes {
+		buffer := make([]byte, recoverySize)
+		if _, err := rand.Read(buffer); err != nil {
+			return nil, nil, err
+		}
+
+		raw := hex.EncodeToString(buffer)
+		codes[i] = raw[:recoverySize] + "-" + raw[recoverySize:]
+		hashes[i] = hashRecovery(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+func hashRecovery(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,191 @@
+package manager
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-log/log"
+)
+
+type LockoutKind string
+
+const (
+	LOCKOUT_USER    LockoutKind = "user"
+	LOCKOUT_ADDRESS LockoutKind = "address"
+)
+
+type Lockout struct {
+	Kind     LockoutKind `json:"kind"`
+	Subject  string      `json:"subject"`
+	Failures int         `json:"failures"`
+	Last     int64       `json:"last"`
+	Until    int64       `json:"until"`
+}
+
+func (l Lockout) remaining(now time.Time) time.Duration {
+	return time.UnixMilli(l.Until).Sub(now)
+}
+
+// ManagerLockout tracks the failed authentication attempts per user and per
+// client address. The counters live in memory only, since a restart already
+// interrupts any ongoing attack.
+type ManagerLockout struct {
+	mu      sync.Mutex
+	config  configuration.Lockout
+	entries map[string]*Lockout
+}
+
+func NewManagerLockout(config configuration.Lockout) *ManagerLockout {
+	return &ManagerLockout{
+		config:  config,
+		entries: make(map[string]*Lockout),
+	}
+}
+
+// Check returns the time left until the user and the address are allowed to
+// authenticate again, or zero if none of them is locked.
+func (m *ManagerLockout) Check(username, address string) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	remaining := time.Duration(0)
+	for _, v := range m.find(username, address) {
+		remaining = max(remaining, v.remaining(now))
+	}
+
+	return remaining
+}
+
+// Fail records a failed attempt and returns the resulting lockout, if any.
+func (m *ManagerLockout) Fail(username, address string) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.purge(now)
+
+	remaining := time.Duration(0)
+	if username != "" {
+		remaining = max(remaining, m.fail(LOCKOUT_USER, username, m.config.Threshold, now))
+	}
+	if address != "" {
+		remaining = max(remaining, m.fail(LOCKOUT_ADDRESS, address, m.config.AddressThreshold, now))
+	}
+
+	return remaining
+}
+
+func (m *ManagerLockout) fail(kind LockoutKind, subject string, threshold int, now time.Time) time.Duration {
+	key := lockoutKey(kind, subject)
+
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &Lockout{
+			Kind:    kind,
+			Subject: subject,
+		}
+		m.entries[key] = entry
+	}
+
+	entry.Failures++
+	entry.Last = now.UnixMilli()
+
+	if entry.Failures < threshold {
+		return 0
+	}
+
+	delay := m.config.Base
+	for i := threshold; i < entry.Failures && delay < m.config.Max; i++ {
+		delay *= 2
+	}
+	delay = min(delay, m.config.Max)
+
+	entry.Until = now.Add(delay).UnixMilli()
+
+	log.Warningf("Too many failed authentications for the %s %q; locked for %s", kind, subject, delay)
+
+	return delay
+}
+
+// Succeed resets the counter of the user. The address counter is kept, so a
+// valid account cannot be used to reset the attempts against other users.
+func (m *ManagerLockout) Succeed(username string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, lockoutKey(LOCKOUT_USER, username))
+}
+
+func (m *ManagerLockout) FindAll() []Lockout {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.purge(time.Now())
+
+	result := make([]Lockout, 0, len(m.entries))
+	for _, v := range m.entries {
+		result = append(result, *v)
+	}
+
+	slices.SortFunc(result, func(a, b Lockout) int {
+		return cmp.Compare(b.Last, a.Last)
+	})
+
+	return result
+}
+
+func (m *ManagerLockout) Clear(kind LockoutKind, subject string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := lockoutKey(kind, subject)
+	if _, ok := m.entries[key]; !ok {
+		return false
+	}
+
+	delete(m.entries, key)
+	log.Messagef("The lockout of the %s %q has been cleared", kind, subject)
+
+	return true
+}
+
+func (m *ManagerLockout) ClearAll() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := len(m.entries)
+	m.entries = make(map[string]*Lockout)
+
+	log.Messagef("%d lockouts have been cleared", count)
+
+	return count
+}
+
+func (m *ManagerLockout) find(username, address string) []*Lockout {
+	result := make([]*Lockout, 0, 2)
+	if entry, ok := m.entries[lockoutKey(LOCKOUT_USER, username)]; ok {
+		result = append(result, entry)
+	}
+	if entry, ok := m.entries[lockoutKey(LOCKOUT_ADDRESS, address)]; ok {
+		result = append(result, entry)
+	}
+	return result
+}
+
+func (m *ManagerLockout) purge(now time.Time) {
+	limit := now.Add(-m.config.Window).UnixMilli()
+	for k, v := range m.entries {
+		if v.Last < limit && v.Until < now.UnixMilli() {
+			delete(m.entries, k)
+		}
+	}
+}
+
+func lockoutKey(kind LockoutKind, subject string) string {
+	return string(kind) + ":" + subject
+}
@@ -0,0 +1,263 @@
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Rafael24595/go-api-render/src/commons/schema"
+	"github.com/Rafael24595/go-api-render/src/domain/namespace"
+	"github.com/Rafael24595/go-api-render/src/domain/web"
+)
+
+const (
+	VIOLATION_INVALID = "invalid"
+	VIOLATION_SCHEMA  = "schema"
+	VIOLATION_QUOTA   = "quota"
+)
+
+var namespaceName = regexp.MustCompile(`^[a-z][a-z0-9_.-]{0,31}$`)
+
+type NamespaceViolation struct {
+	Namespace string
+	Path      string
+	Code      string
+	Message   string
+}
+
+type compiledSchema struct {
+	modified int64
+	schema   *schema.Schema
+}
+
+// ManagerNamespace keeps the schemas and quotas of the web data namespaces.
+// Namespaces that are not registered accept any value within the default
+// quota, and the whole web data of a user stays within the owner quota.
+type ManagerNamespace struct {
+	mu         sync.Mutex
+	namespace  namespace.Repository
+	quota      int64
+	ownerQuota int64
+	compiled   map[string]compiledSchema
+}
+
+func NewManagerNamespace(namespace namespace.Repository, quota, ownerQuota int64) *ManagerNamespace {
+	return &ManagerNamespace{
+		namespace:  namespace,
+		quota:      quota,
+		ownerQuota: ownerQuota,
+		compiled:   make(map[string]compiledSchema),
+	}
+}
+
+func (m *ManagerNamespace) FindAll() []namespace.Namespace {
+	result := m.namespace.FindAll()
+	slices.SortFunc(result, func(a, b namespace.Namespace) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result
+}
+
+func (m *ManagerNamespace) Find(name string) (*namespace.Namespace, bool) {
+	result, ok := m.namespace.FindByName(name)
+	if !ok || result == nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// Resolve creates or replaces a namespace. An empty schema accepts any value.
+func (m *ManagerNamespace) Resolve(actor, name, description string, raw json.RawMessage, quota int64) (*namespace.Namespace, error) {
+	if !namespaceName.MatchString(name) {
+		return nil, fmt.Errorf("the namespace name %q is not valid", name)
+	}
+
+	if quota < 0 {
+		return nil, fmt.Errorf("the quota of %q cannot be negative", name)
+	}
+
+	definition := ""
+	if len(raw) > 0 && string(raw) != "null" {
+		if _, err := schema.Compile(raw); err != nil {
+			return nil, err
+		}
+		buffer := new(bytes.Buffer)
+		if err := json.Compact(buffer, raw); err != nil {
+			return nil, err
+		}
+		definition = buffer.String()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := namespace.NewNamespace(name, description, definition, quota)
+	if current, ok := m.namespace.FindByName(name); ok && current != nil {
+		result.Id = current.Id
+		result.Timestamp = current.Timestamp
+	}
+
+	delete(m.compiled, name)
+
+	return m.namespace.Resolve(actor, result), nil
+}
+
+// Delete removes a namespace. The data the users keep in it is no longer
+// validated and falls back to the default quota.
+func (m *ManagerNamespace) Delete(name string) *namespace.Namespace {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.namespace.FindByName(name)
+	if !ok || current == nil {
+		return nil
+	}
+
+	delete(m.compiled, name)
+
+	return m.namespace.Delete(current)
+}
+
+// Quota returns the size limit of the namespace in bytes, 0 if unlimited.
+func (m *ManagerNamespace) Quota(name string) int64 {
+	if found, ok := m.Find(name); ok && found.Quota > 0 {
+		return found.Quota
+	}
+	return m.quota
+}
+
+// Validate returns the reasons why the value cannot be stored in the
+// namespace.
+func (m *ManagerNamespace) Validate(name string, value web.Value) []NamespaceViolation {
+	if !namespaceName.MatchString(name) {
+		return []NamespaceViolation{
+			namespaceViolation(name, "", VIOLATION_INVALID, fmt.Sprintf("the namespace name %q is not valid", name)),
+		}
+	}
+
+	if quota := m.Quota(name); quota > 0 && int64(len(value)) > quota {
+		return []NamespaceViolation{
+			namespaceViolation(name, "", VIOLATION_QUOTA, fmt.Sprintf("the namespace exceeds the limit of %d bytes", quota)),
+		}
+	}
+
+	compiled, err := m.schema(name)
+	if err != nil {
+		return []NamespaceViolation{
+			namespaceViolation(name, "", VIOLATION_SCHEMA, err.Error()),
+		}
+	}
+
+	if compiled == nil {
+		return nil
+	}
+
+	decoded, err := value.Decode()
+	if err != nil {
+		return []NamespaceViolation{
+			namespaceViolation(name, "", VIOLATION_INVALID, "the value is not valid JSON"),
+		}
+	}
+
+	violations := make([]NamespaceViolation, 0)
+	for _, v := range compiled.Validate(decoded) {
+		violations = append(violations, namespaceViolation(name, v.Path, VIOLATION_SCHEMA, v.Message))
+	}
+
+	return violations
+}
+
+// ValidateData checks the flat data, which predates the namespaces, against
+// the default quota.
+func (m *ManagerNamespace) ValidateData(data map[string]string) []NamespaceViolation {
+	if m.quota <= 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return []NamespaceViolation{
+			namespaceViolation("", "", VIOLATION_INVALID, err.Error()),
+		}
+	}
+
+	if int64(len(encoded)) > m.quota {
+		return []NamespaceViolation{
+			namespaceViolation("", "", VIOLATION_QUOTA, fmt.Sprintf("the web data exceeds the limit of %d bytes", m.quota)),
+		}
+	}
+
+	return nil
+}
+
+// ValidateOwner checks the size of the whole web data of a user, so the quota
+// cannot be dodged by spreading the data over many namespaces. Data already
+// over the quota can still shrink.
+func (m *ManagerNamespace) ValidateOwner(current *web.WebData, data map[string]string, namespaces map[string]web.Value) []NamespaceViolation {
+	if m.ownerQuota <= 0 {
+		return nil
+	}
+
+	size := ownerSize(data, namespaces)
+	if size <= m.ownerQuota {
+		return nil
+	}
+
+	if current != nil && size <= ownerSize(current.Data, current.Namespaces) {
+		return nil
+	}
+
+	return []NamespaceViolation{
+		namespaceViolation("", "", VIOLATION_QUOTA, fmt.Sprintf("the web data of the user exceeds the limit of %d bytes", m.ownerQuota)),
+	}
+}
+
+func ownerSize(data map[string]string, namespaces map[string]web.Value) int64 {
+	size := int64(0)
+	for k, v := range data {
+		size += int64(len(k) + len(v))
+	}
+	for k, v := range namespaces {
+		size += int64(len(k) + len(v))
+	}
+	return size
+}
+
+func (m *ManagerNamespace) schema(name string) (*schema.Schema, error) {
+	found, ok := m.Find(name)
+	if !ok || found.Schema == "" {
+		return nil, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cached, ok := m.compiled[name]; ok && cached.modified == found.Modified {
+		return cached.schema, nil
+	}
+
+	compiled, err := schema.Compile([]byte(found.Schema))
+	if err != nil {
+		return nil, err
+	}
+
+	m.compiled[name] = compiledSchema{
+		modified: found.Modified,
+		schema:   compiled,
+	}
+
+	return compiled, nil
+}
+
+func namespaceViolation(name, path, code, message string) NamespaceViolation {
+	return NamespaceViolation{
+		Namespace: name,
+		Path:      path,
+		Code:      code,
+		Message:   message,
+	}
+}
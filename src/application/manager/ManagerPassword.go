@@ -0,0 +1,214 @@
+package manager
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/domain/account"
+	"github.com/Rafael24595/go-log/log"
+)
+
+const (
+	VIOLATION_MIN_LENGTH = "min_length"
+	VIOLATION_CLASSES    = "character_classes"
+	VIOLATION_BANNED     = "banned"
+	VIOLATION_REUSED     = "reused"
+)
+
+const digestScheme = "pbkdf2-sha256"
+const digestIterations = 100_000
+const digestSaltLength = 16
+const digestKeyLength = 32
+
+type PasswordViolation struct {
+	Code    string
+	Message string
+}
+
+// ManagerPassword applies the password policy the core sessions do not
+// enforce. The previous passwords are kept as salted PBKDF2 digests in the
+// account of the user.
+type ManagerPassword struct {
+	policy         configuration.Password
+	managerAccount *ManagerAccount
+}
+
+func NewManagerPassword(policy configuration.Password, managerAccount *ManagerAccount) *ManagerPassword {
+	return &ManagerPassword{
+		policy:         policy,
+		managerAccount: managerAccount,
+	}
+}
+
+// Check returns the rules of the policy the password breaks. The current
+// password, when known, counts as a previous one.
+func (m *ManagerPassword) Check(owner, password, current string) []PasswordViolation {
+	violations := make([]PasswordViolation, 0)
+
+	if len([]rune(password)) < m.policy.MinLength {
+		violations = append(violations, PasswordViolation{
+			Code:    VIOLATION_MIN_LENGTH,
+			Message: fmt.Sprintf("the password must have at least %d characters", m.policy.MinLength),
+		})
+	}
+
+	if classes := passwordClasses(password); classes < m.policy.Classes {
+		violations = append(violations, PasswordViolation{
+			Code:    VIOLATION_CLASSES,
+			Message: fmt.Sprintf("the password must combine at least %d of lowercase letters, uppercase letters, digits and symbols", m.policy.Classes),
+		})
+	}
+
+	if slices.Contains(m.policy.Banned, strings.ToLower(password)) {
+		violations = append(violations, PasswordViolation{
+			Code:    VIOLATION_BANNED,
+			Message: "the password is too common",
+		})
+	}
+
+	if m.policy.History > 0 && m.isReused(owner, password, current) {
+		violations = append(violations, PasswordViolation{
+			Code:    VIOLATION_REUSED,
+			Message: fmt.Sprintf("the password cannot be any of the last %d ones", m.policy.History),
+		})
+	}
+
+	return violations
+}
+
+// Changed records the new password of the user and restarts its age.
+func (m *ManagerPassword) Changed(owner, password string) {
+	digest, err := passwordDigest(password)
+	if err != nil {
+		log.Errorf("The password history of %q cannot be updated: %s", owner, err.Error())
+		return
+	}
+
+	m.managerAccount.RecordPassword(owner, digest, m.policy.History)
+}
+
+// IsExpired tells whether the password of the user is older than the allowed
+// age. Users created before the policy count from their registration and the
+// users of an identity provider never expire, as they do not use it.
+func (m *ManagerPassword) IsExpired(owner string) bool {
+	if m.policy.MaxAge == 0 {
+		return false
+	}
+
+	result, ok := m.managerAccount.Find(owner)
+	if !ok || result.Provider == account.PROVIDER_OIDC {
+		return false
+	}
+
+	changed := result.Changed
+	if changed == 0 {
+		changed = result.Timestamp
+	}
+
+	return time.Since(time.UnixMilli(changed)) > m.policy.MaxAge
+}
+
+func (m *ManagerPassword) isReused(owner, password, current string) bool {
+	if current != "" && subtle.ConstantTimeCompare([]byte(password), []byte(current)) == 1 {
+		return true
+	}
+
+	result, ok := m.managerAccount.Find(owner)
+	if !ok {
+		return false
+	}
+
+	for i, v := range result.Passwords {
+		if i >= m.policy.History {
+			break
+		}
+		if passwordMatches(v, password) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func passwordClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	count := 0
+	for _, v := range []bool{lower, upper, digit, symbol} {
+		if v {
+			count++
+		}
+	}
+
+	return count
+}
+
+func passwordDigest(password string) (string, error) {
+	salt := make([]byte, digestSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, digestIterations, digestKeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		digestScheme,
+		strconv.Itoa(digestIterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+func passwordMatches(digest, password string) bool {
+	fragments := strings.Split(digest, "$")
+	if len(fragments) != 4 || fragments[0] != digestScheme {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(fragments[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(fragments[2])
+	if err != nil {
+		return false
+	}
+
+	expected, err := base64.RawStdEncoding.DecodeString(fragments[3])
+	if err != nil {
+		return false
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(expected))
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
@@ -0,0 +1,137 @@
+package manager
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/domain/revision"
+	"github.com/Rafael24595/go-api-render/src/domain/web"
+)
+
+// ManagerRevision keeps the previous versions of the web data of the users,
+// so a bad change can be undone. The revisions beyond the configured count or
+// age are pruned.
+type ManagerRevision struct {
+	mu       sync.Mutex
+	revision revision.Repository
+	history  configuration.WebHistory
+}
+
+func NewManagerRevision(revision revision.Repository, history configuration.WebHistory) *ManagerRevision {
+	return &ManagerRevision{
+		revision: revision,
+		history:  history,
+	}
+}
+
+// FindByOwner returns the revisions of the user, the newest first.
+func (m *ManagerRevision) FindByOwner(owner string) []revision.Revision {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.prune(owner)
+}
+
+func (m *ManagerRevision) Find(owner string, number int64) (*revision.Revision, bool) {
+	for _, v := range m.FindByOwner(owner) {
+		if v.Number == number {
+			return &v, true
+		}
+	}
+	return nil, false
+}
+
+// Prepare numbers the revision kept when the previous version of the data is
+// replaced by the next one, and stamps the next one with it. Nothing is kept
+// when the previous version is empty or the content does not change. The
+// last number is stored with the data, so a number is never given twice, even
+// once every revision has been pruned.
+func (m *ManagerRevision) Prepare(previous web.WebData, next *web.WebData) bool {
+	next.Revision = previous.Revision
+
+	if !m.history.Enabled() || (len(previous.Data) == 0 && len(previous.Namespaces) == 0) {
+		return false
+	}
+
+	before, err := previous.Content()
+	if err != nil {
+		return false
+	}
+
+	after, err := next.Content()
+	if err != nil || bytes.Equal(before, after) {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	number := previous.Revision + 1
+	for _, v := range m.revision.FindByOwner(previous.Owner) {
+		number = max(number, v.Number+1)
+	}
+
+	next.Revision = number
+
+	return true
+}
+
+// Record keeps the previous version of the data as the revision numbered by
+// Prepare on the next one.
+func (m *ManagerRevision) Record(previous, next web.WebData) {
+	before, err := previous.Content()
+	if err != nil {
+		return
+	}
+
+	after, err := next.Content()
+	if err != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	size := int64(len(before))
+	change := int64(len(after)) - size
+
+	m.revision.Insert(revision.NewRevision(next.Revision, previous, size, change))
+
+	m.prune(previous.Owner)
+}
+
+func (m *ManagerRevision) DeleteAll(owner string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.revision.Delete(m.revision.FindByOwner(owner)...)
+}
+
+func (m *ManagerRevision) prune(owner string) []revision.Revision {
+	revisions := m.revision.FindByOwner(owner)
+	slices.SortFunc(revisions, func(a, b revision.Revision) int {
+		return cmp.Compare(b.Number, a.Number)
+	})
+
+	limit := time.Now().Add(-m.history.MaxAge).UnixMilli()
+
+	kept := make([]revision.Revision, 0, len(revisions))
+	expired := make([]revision.Revision, 0)
+	for _, v := range revisions {
+		if len(kept) >= m.history.Limit || (m.history.MaxAge > 0 && v.Timestamp < limit) {
+			expired = append(expired, v)
+			continue
+		}
+		kept = append(kept, v)
+	}
+
+	if len(expired) > 0 {
+		m.revision.Delete(expired...)
+	}
+
+	return kept
+}
@@ -0,0 +1,170 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	domain_session "github.com/Rafael24595/go-api-core/src/domain/session"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+)
+
+var roleName = regexp.MustCompile(`^[a-z][a-z0-9_.-]{0,31}$`)
+
+// ManagerRole resolves the permissions bundled by the roles. The admin role is
+// built in and grants every permission. The user role is held by every user
+// and its default permissions may be changed like any other role.
+type ManagerRole struct {
+	mu   sync.Mutex
+	role role.Repository
+}
+
+func NewManagerRole(role role.Repository) *ManagerRole {
+	return &ManagerRole{
+		role: role,
+	}
+}
+
+func (m *ManagerRole) FindAll() []role.Role {
+	result := []role.Role{
+		*adminRole(),
+	}
+
+	stored := m.role.FindAll()
+	if !slices.ContainsFunc(stored, func(r role.Role) bool { return r.Name == role.ROLE_USER }) {
+		result = append(result, *userRole())
+	}
+
+	for _, v := range stored {
+		if v.Name != string(domain_session.ROLE_ADMIN) {
+			result = append(result, v)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b role.Role) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result
+}
+
+func (m *ManagerRole) Find(name string) (*role.Role, bool) {
+	if name == string(domain_session.ROLE_ADMIN) {
+		return adminRole(), true
+	}
+
+	result, ok := m.role.FindByName(name)
+	if ok && result != nil {
+		return result, true
+	}
+
+	if name == role.ROLE_USER {
+		return userRole(), true
+	}
+
+	return nil, false
+}
+
+func (m *ManagerRole) Exists(name string) bool {
+	_, ok := m.Find(name)
+	return ok
+}
+
+// Resolve creates or replaces a role. The admin role cannot be modified.
+func (m *ManagerRole) Resolve(actor, name, description string, permissions []string) (*role.Role, error) {
+	if !roleName.MatchString(name) {
+		return nil, fmt.Errorf("the role name %q is not valid", name)
+	}
+
+	if name == string(domain_session.ROLE_ADMIN) {
+		return nil, errors.New("the admin role cannot be modified")
+	}
+
+	granted := make([]role.Permission, 0, len(permissions))
+	for _, v := range permissions {
+		if !role.IsPermission(v) {
+			return nil, fmt.Errorf("the permission %q does not exist", v)
+		}
+		if !slices.Contains(granted, role.Permission(v)) {
+			granted = append(granted, role.Permission(v))
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := role.NewRole(name, description, granted...)
+	if current, ok := m.role.FindByName(name); ok && current != nil {
+		result.Id = current.Id
+		result.Timestamp = current.Timestamp
+	}
+
+	return m.role.Resolve(actor, result), nil
+}
+
+// Delete removes a role. Users holding it keep the name but lose its
+// permissions until a role with the same name is defined again.
+func (m *ManagerRole) Delete(name string) (*role.Role, error) {
+	if name == string(domain_session.ROLE_ADMIN) || name == role.ROLE_USER {
+		return nil, fmt.Errorf("the %q role is built in and cannot be deleted", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.role.FindByName(name)
+	if !ok || current == nil {
+		return nil, nil
+	}
+
+	return m.role.Delete(current), nil
+}
+
+// Permissions returns the permissions bundled by the roles, including the
+// ones of the user role.
+func (m *ManagerRole) Permissions(roles []domain_session.Role) []role.Permission {
+	names := make([]string, 0, len(roles)+1)
+	names = append(names, role.ROLE_USER)
+	for _, v := range roles {
+		names = append(names, string(v))
+	}
+
+	result := make([]role.Permission, 0)
+	for _, name := range names {
+		found, ok := m.Find(name)
+		if !ok {
+			continue
+		}
+		for _, p := range found.Permissions {
+			if !slices.Contains(result, role.Permission(p)) {
+				result = append(result, role.Permission(p))
+			}
+		}
+	}
+
+	return result
+}
+
+func (m *ManagerRole) IsGranted(roles []domain_session.Role, permission role.Permission) bool {
+	if permission == role.PERMISSION_NONE {
+		return true
+	}
+	return slices.Contains(m.Permissions(roles), permission)
+}
+
+func adminRole() *role.Role {
+	return role.NewRole(string(domain_session.ROLE_ADMIN), "Grants every permission.",
+		role.Permissions()...)
+}
+
+func userRole() *role.Role {
+	return role.NewRole(role.ROLE_USER, "Held by every user.",
+		role.PERMISSION_COLLECTIONS_READ,
+		role.PERMISSION_COLLECTIONS_WRITE,
+		role.PERMISSION_MOCK_MANAGE,
+		role.PERMISSION_TOKENS_MANAGE,
+	)
+}
@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Rafael24595/go-api-render/src/domain/scope"
+)
+
+// ManagerScope keeps the scopes and restrictions of the API tokens, which
+// the core tokens do not model.
+type ManagerScope struct {
+	mu    sync.Mutex
+	scope scope.Repository
+}
+
+func NewManagerScope(scope scope.Repository) *ManagerScope {
+	return &ManagerScope{
+		scope: scope,
+	}
+}
+
+func (m *ManagerScope) Find(token string) (*scope.Policy, bool) {
+	result, ok := m.scope.FindByToken(token)
+	if !ok || result == nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// Define stores the policy of a token. Nothing is stored if no scopes are
+// given, so the token keeps the full access of its owner; callers creating an
+// API token must give the default scopes instead.
+func (m *ManagerScope) Define(owner, token string, scopes, collections, endPoints []string) (*scope.Policy, error) {
+	if err := m.Validate(scopes, collections, endPoints); err != nil {
+		return nil, err
+	}
+
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	policy := scope.NewPolicy(token, compact(scopes), compact(collections), compact(endPoints))
+	if current, ok := m.Find(token); ok {
+		policy.Id = current.Id
+		policy.Timestamp = current.Timestamp
+	}
+
+	return m.scope.Resolve(owner, policy), nil
+}
+
+func (m *ManagerScope) Validate(scopes, collections, endPoints []string) error {
+	for _, v := range scopes {
+		if !scope.IsScope(v) {
+			return fmt.Errorf("the scope %q does not exist", v)
+		}
+	}
+
+	if len(scopes) == 0 && (len(collections) > 0 || len(endPoints) > 0) {
+		return errors.New("the restrictions require at least one scope")
+	}
+
+	return nil
+}
+
+func (m *ManagerScope) Delete(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if result, ok := m.Find(token); ok {
+		m.scope.Delete(result)
+	}
+}
+
+func compact(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" && !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"strconv"
+
+	"github.com/Rafael24595/go-api-render/src/domain/setting"
+)
+
+type ManagerSetting struct {
+	setting setting.Repository
+}
+
+func NewManagerSetting(setting setting.Repository) *ManagerSetting {
+	return &ManagerSetting{
+		setting: setting,
+	}
+}
+
+func (m *ManagerSetting) FindAll() []setting.Setting {
+	return m.setting.FindAll()
+}
+
+func (m *ManagerSetting) Find(id string) (string, bool) {
+	result, ok := m.setting.Find(id)
+	if !ok || result == nil {
+		return "", false
+	}
+	return result.Value, true
+}
+
+func (m *ManagerSetting) FindBool(id string, def bool) bool {
+	value, ok := m.Find(id)
+	if !ok {
+		return def
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return def
+	}
+
+	return result
+}
+
+func (m *ManagerSetting) Resolve(owner, id, value string) *setting.Setting {
+	return m.setting.Resolve(owner, setting.NewSetting(id, value))
+}
+
+func (m *ManagerSetting) ResolveBool(owner, id string, value bool) *setting.Setting {
+	return m.Resolve(owner, id, strconv.FormatBool(value))
+}
@@ -0,0 +1,694 @@
+package manager
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"time"
+
+	core_manager "github.com/Rafael24595/go-api-core/src/application/manager"
+	"github.com/Rafael24595/go-api-core/src/application/session"
+	"github.com/Rafael24595/go-api-core/src/domain/action"
+	"github.com/Rafael24595/go-api-core/src/domain/context"
+	"github.com/Rafael24595/go-api-core/src/domain/mock"
+	"github.com/Rafael24595/go-api-core/src/domain/token"
+	"github.com/Rafael24595/go-api-core/src/infrastructure/dto"
+	"github.com/Rafael24595/go-api-render/src/domain/scope"
+	"github.com/Rafael24595/go-api-render/src/domain/web"
+)
+
+const TAKEOUT_FORMAT = "go-api-render-takeout"
+const TAKEOUT_VERSION = 1
+
+const takeoutManifest = "manifest.json"
+const takeoutChecksums = "SHA256SUMS"
+
+const (
+	takeoutCollections = "collections.json"
+	takeoutRequests    = "requests.json"
+	takeoutHistoric    = "historic.json"
+	takeoutContexts    = "contexts.json"
+	takeoutEndPoints   = "mock/endpoints.json"
+	takeoutMetrics     = "mock/metrics.json"
+	takeoutTokens      = "tokens.json"
+	takeoutWeb         = "web.json"
+)
+
+const takeoutContextRequests = "requests"
+const takeoutContextHistoric = "historic"
+
+// TAKEOUT_FILE_LIMIT bounds the uncompressed size of every file read from an
+// archive.
+const TAKEOUT_FILE_LIMIT = 64 << 20
+
+// TAKEOUT_TOTAL_LIMIT bounds the uncompressed size of all the files read from
+// an archive.
+const TAKEOUT_TOTAL_LIMIT = 256 << 20
+
+type TakeoutManifest struct {
+	Format    string        `json:"format"`
+	Version   int           `json:"version"`
+	Owner     string        `json:"owner"`
+	Timestamp int64         `json:"timestamp"`
+	Files     []TakeoutFile `json:"files"`
+}
+
+type TakeoutFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+// Takeout holds the collected data of a user, ready to be archived.
+type Takeout struct {
+	Owner   string
+	entries []takeoutEntry
+}
+
+type takeoutEntry struct {
+	name    string
+	content []byte
+}
+
+// TakeoutError reports an archive that cannot be imported.
+type TakeoutError struct {
+	File    string
+	Message string
+}
+
+func (e TakeoutError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("the takeout archive is not valid: %s", e.Message)
+	}
+	return fmt.Sprintf("the file %q of the takeout archive is not valid: %s", e.File, e.Message)
+}
+
+// TakeoutImport sets how an archive is imported. A dry run checks the archive
+// and reports what would change without storing anything. Remapping gives new
+// identifiers to the imported items, so the archive can be merged into an
+// account that already holds them; otherwise the identifiers are kept and the
+// items must not exist yet.
+type TakeoutImport struct {
+	DryRun bool `json:"dry_run"`
+	Remap  bool `json:"remap"`
+}
+
+type TakeoutReport struct {
+	TakeoutImport
+	Applied     bool                 `json:"applied"`
+	Source      string               `json:"source"`
+	Collections int                  `json:"collections"`
+	Requests    int                  `json:"requests"`
+	Historic    int                  `json:"historic"`
+	Contexts    int                  `json:"contexts"`
+	EndPoints   int                  `json:"end_points"`
+	WebData     int                  `json:"web_data"`
+	Conflicts   []string             `json:"conflicts"`
+	Skipped     []string             `json:"skipped"`
+	Violations  []NamespaceViolation `json:"violations"`
+}
+
+type takeoutEndPointMetrics struct {
+	EndPoint string        `json:"end_point"`
+	Metrics  *mock.Metrics `json:"metrics"`
+}
+
+// takeoutToken is a token along with its scope policy, if it has one.
+type takeoutToken struct {
+	token.LiteToken
+	Policy *scope.Policy `json:"policy,omitempty"`
+}
+
+// ManagerTakeout gathers everything a user owns into a ZIP archive. The
+// manifest lists the SHA-256 checksum of every file, which is also written
+// in the format of sha256sum so the archive can be verified with it.
+type ManagerTakeout struct {
+	managerRequest     *core_manager.ManagerRequest
+	managerContext     *core_manager.ManagerContext
+	managerCollection  *core_manager.ManagerCollection
+	managerGroup       *core_manager.ManagerGroup
+	managerEndPoint    *core_manager.ManagerEndPoint
+	managerMetrics     *core_manager.ManagerMetrics
+	managerToken       *core_manager.ManagerToken
+	managerSessionData *session.ManagerSessionData
+	managerWeb         *ManagerWeb
+	managerScope       *ManagerScope
+}
+
+func NewManagerTakeout(
+	managerRequest *core_manager.ManagerRequest,
+	managerContext *core_manager.ManagerContext,
+	managerCollection *core_manager.ManagerCollection,
+	managerGroup *core_manager.ManagerGroup,
+	managerEndPoint *core_manager.ManagerEndPoint,
+	managerMetrics *core_manager.ManagerMetrics,
+	managerToken *core_manager.ManagerToken,
+	managerSessionData *session.ManagerSessionData,
+	managerWeb *ManagerWeb,
+	managerScope *ManagerScope,
+) *ManagerTakeout {
+	return &ManagerTakeout{
+		managerRequest:     managerRequest,
+		managerContext:     managerContext,
+		managerCollection:  managerCollection,
+		managerGroup:       managerGroup,
+		managerEndPoint:    managerEndPoint,
+		managerMetrics:     managerMetrics,
+		managerToken:       managerToken,
+		managerSessionData: managerSessionData,
+		managerWeb:         managerWeb,
+		managerScope:       managerScope,
+	}
+}
+
+// Collect reads the data of the user. It is kept apart from the writing, so
+// a failure is known before the archive starts to be sent.
+func (m *ManagerTakeout) Collect(owner string) (*Takeout, error) {
+	group, err := m.managerSessionData.FindCollections(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	persistent, err := m.managerSessionData.FindPersistent(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	transient, err := m.managerSessionData.FindTransient(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	contexts := make(map[string]*context.Context)
+	if found, ok := m.managerContext.Find(owner, persistent.Context); ok {
+		contexts[takeoutContextRequests] = found
+	}
+	if found, ok := m.managerContext.Find(owner, transient.Context); ok {
+		contexts[takeoutContextHistoric] = found
+	}
+
+	endPoints := m.managerEndPoint.Export(owner)
+
+	metrics := make([]takeoutEndPointMetrics, 0, len(endPoints))
+	for i := range endPoints {
+		if found, ok := m.managerMetrics.Find(owner, &endPoints[i]); ok {
+			metrics = append(metrics, takeoutEndPointMetrics{
+				EndPoint: endPoints[i].Id,
+				Metrics:  found,
+			})
+		}
+	}
+
+	tokens := make([]takeoutToken, 0)
+	for _, v := range m.managerToken.FindAll(owner) {
+		policy, _ := m.managerScope.Find(v.Id)
+		tokens = append(tokens, takeoutToken{
+			LiteToken: v,
+			Policy:    policy,
+		})
+	}
+
+	webData, _ := m.managerWeb.FindByOwner(owner)
+
+	sections := []struct {
+		name    string
+		payload any
+	}{
+		{takeoutCollections, m.managerCollection.Export(owner, group.Nodes...)},
+		{takeoutRequests, m.managerRequest.Export(owner, persistent.Nodes...)},
+		{takeoutHistoric, m.managerRequest.Export(owner, transient.Nodes...)},
+		{takeoutContexts, contexts},
+		{takeoutEndPoints, endPoints},
+		{takeoutMetrics, metrics},
+		{takeoutTokens, tokens},
+		{takeoutWeb, webData},
+	}
+
+	entries := make([]takeoutEntry, len(sections))
+	for i, v := range sections {
+		content, err := json.MarshalIndent(v.payload, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("the %s of the takeout cannot be encoded: %s", v.name, err.Error())
+		}
+		entries[i] = takeoutEntry{
+			name:    v.name,
+			content: content,
+		}
+	}
+
+	return &Takeout{
+		Owner:   owner,
+		entries: entries,
+	}, nil
+}
+
+// Write streams the archive with the collected entries, the manifest and
+// the checksums.
+func (t *Takeout) Write(w io.Writer) (*TakeoutManifest, error) {
+	now := time.Now()
+
+	manifest := &TakeoutManifest{
+		Format:    TAKEOUT_FORMAT,
+		Version:   TAKEOUT_VERSION,
+		Owner:     t.Owner,
+		Timestamp: now.UnixMilli(),
+		Files:     make([]TakeoutFile, 0, len(t.entries)),
+	}
+
+	archive := zip.NewWriter(w)
+
+	for _, v := range t.entries {
+		file, err := writeTakeoutEntry(archive, v.name, v.content, now)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, *file)
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writeTakeoutEntry(archive, takeoutManifest, content, now); err != nil {
+		return nil, err
+	}
+
+	checksums := new(strings.Builder)
+	for _, v := range manifest.Files {
+		fmt.Fprintf(checksums, "%s  %s\n", v.Sha256, v.Name)
+	}
+
+	if _, err := writeTakeoutEntry(archive, takeoutChecksums, []byte(checksums.String()), now); err != nil {
+		return nil, err
+	}
+
+	return manifest, archive.Close()
+}
+
+func writeTakeoutEntry(archive *zip.Writer, name string, content []byte, modified time.Time) (*TakeoutFile, error) {
+	writer, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(content)
+
+	return &TakeoutFile{
+		Name:   name,
+		Size:   int64(len(content)),
+		Sha256: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// ReadTakeout opens an archive written by Write. The manifest must be of a
+// known version and every file must match its size and checksum. The manifest
+// is read first, so no file it does not list is ever decompressed, and the
+// files read are bounded one by one and altogether.
+func ReadTakeout(reader io.ReaderAt, size int64) (*Takeout, error) {
+	archive, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, TakeoutError{Message: err.Error()}
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, v := range archive.File {
+		if _, ok := files[v.Name]; ok {
+			return nil, TakeoutError{File: v.Name, Message: "the file is repeated"}
+		}
+		files[v.Name] = v
+	}
+
+	file, ok := files[takeoutManifest]
+	if !ok {
+		return nil, TakeoutError{Message: "the manifest is missing"}
+	}
+
+	budget := int64(TAKEOUT_TOTAL_LIMIT)
+
+	content, err := readTakeoutFile(file, &budget)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest TakeoutManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, TakeoutError{File: takeoutManifest, Message: err.Error()}
+	}
+
+	if manifest.Format != TAKEOUT_FORMAT {
+		return nil, TakeoutError{File: takeoutManifest, Message: fmt.Sprintf("unknown format %q", manifest.Format)}
+	}
+	if manifest.Version < 1 || manifest.Version > TAKEOUT_VERSION {
+		return nil, TakeoutError{File: takeoutManifest, Message: fmt.Sprintf("unsupported version %d", manifest.Version)}
+	}
+
+	listed := make(map[string]bool, len(manifest.Files))
+	for _, v := range manifest.Files {
+		if v.Name == takeoutManifest || v.Name == takeoutChecksums || listed[v.Name] {
+			return nil, TakeoutError{File: v.Name, Message: "the file is listed more than once"}
+		}
+		listed[v.Name] = true
+	}
+
+	for name := range files {
+		if name != takeoutManifest && name != takeoutChecksums && !listed[name] {
+			return nil, TakeoutError{File: name, Message: "the file is not listed in the manifest"}
+		}
+	}
+
+	entries := make([]takeoutEntry, 0, len(manifest.Files))
+	checksums := new(strings.Builder)
+
+	for _, v := range manifest.Files {
+		file, ok := files[v.Name]
+		if !ok {
+			return nil, TakeoutError{File: v.Name, Message: "the file is missing"}
+		}
+
+		if file.UncompressedSize64 != uint64(v.Size) {
+			return nil, TakeoutError{File: v.Name, Message: "the size does not match"}
+		}
+
+		content, err := readTakeoutFile(file, &budget)
+		if err != nil {
+			return nil, err
+		}
+
+		sum := sha256.Sum256(content)
+		if int64(len(content)) != v.Size || hex.EncodeToString(sum[:]) != v.Sha256 {
+			return nil, TakeoutError{File: v.Name, Message: "the checksum does not match"}
+		}
+
+		entries = append(entries, takeoutEntry{
+			name:    v.Name,
+			content: content,
+		})
+		fmt.Fprintf(checksums, "%s  %s\n", v.Sha256, v.Name)
+	}
+
+	if file, ok := files[takeoutChecksums]; ok {
+		content, err := readTakeoutFile(file, &budget)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(content, []byte(checksums.String())) {
+			return nil, TakeoutError{File: takeoutChecksums, Message: "the checksums do not match the manifest"}
+		}
+	}
+
+	return &Takeout{
+		Owner:   manifest.Owner,
+		entries: entries,
+	}, nil
+}
+
+// readTakeoutFile decompresses a file of the archive, bounded by the file
+// limit and by the budget left for the whole archive, which it consumes.
+func readTakeoutFile(file *zip.File, budget *int64) ([]byte, error) {
+	limit := min(int64(TAKEOUT_FILE_LIMIT), *budget)
+	if file.UncompressedSize64 > uint64(limit) {
+		return nil, TakeoutError{File: file.Name, Message: "the file is too large"}
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, TakeoutError{File: file.Name, Message: err.Error()}
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, TakeoutError{File: file.Name, Message: err.Error()}
+	}
+	if int64(len(content)) > limit {
+		return nil, TakeoutError{File: file.Name, Message: "the file is too large"}
+	}
+
+	*budget -= int64(len(content))
+
+	return content, nil
+}
+
+type takeoutContent struct {
+	collections []dto.DtoCollection
+	requests    []action.Request
+	historic    []action.Request
+	contexts    map[string]*context.Context
+	endPoints   []mock.EndPoint
+	webData     *web.WebData
+}
+
+// Import recreates the content of the archive for the given user. Everything
+// is decoded and checked before the first item is stored, so an archive that
+// cannot be imported leaves the account untouched. Metrics and tokens are not
+// imported: the former are rebuilt by use and the latter carry no secret.
+func (m *ManagerTakeout) Import(owner string, takeout *Takeout, options TakeoutImport) (*TakeoutReport, error) {
+	content, skipped, err := takeout.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Remap {
+		content.remap()
+	}
+
+	report := &TakeoutReport{
+		TakeoutImport: options,
+		Source:        takeout.Owner,
+		Collections:   len(content.collections),
+		Requests:      len(content.requests),
+		Historic:      len(content.historic),
+		Contexts:      len(content.contexts),
+		EndPoints:     len(content.endPoints),
+		Conflicts:     make([]string, 0),
+		Skipped:       skipped,
+		Violations:    make([]NamespaceViolation, 0),
+	}
+
+	if !options.Remap {
+		report.Conflicts = m.conflicts(owner, content)
+	}
+
+	merged := m.mergeWebData(owner, content.webData)
+	if merged != nil {
+		report.WebData = len(content.webData.Data) + len(content.webData.Namespaces)
+
+		current, _ := m.managerWeb.FindByOwner(owner)
+		var webError WebDataError
+		if err := m.managerWeb.validate(current, merged); errors.As(err, &webError) {
+			report.Violations = webError.Violations
+		}
+	}
+
+	if options.DryRun || len(report.Conflicts) > 0 || len(report.Violations) > 0 {
+		return report, nil
+	}
+
+	if err := m.apply(owner, content, merged); err != nil {
+		return report, err
+	}
+
+	report.Applied = true
+
+	return report, nil
+}
+
+func (m *ManagerTakeout) apply(owner string, content *takeoutContent, webData *web.WebData) error {
+	group, err := m.managerSessionData.FindCollections(owner)
+	if err != nil {
+		return err
+	}
+
+	persistent, err := m.managerSessionData.FindPersistent(owner)
+	if err != nil {
+		return err
+	}
+
+	transient, err := m.managerSessionData.FindTransient(owner)
+	if err != nil {
+		return err
+	}
+
+	if len(content.collections) > 0 {
+		if _, _, err := m.managerGroup.ImportDtoCollections(owner, group, content.collections...); err != nil {
+			return err
+		}
+	}
+
+	if len(content.requests) > 0 {
+		m.managerCollection.ImportRequests(owner, persistent, content.requests...)
+	}
+	if len(content.historic) > 0 {
+		m.managerCollection.ImportRequests(owner, transient, content.historic...)
+	}
+
+	m.mergeContext(owner, persistent.Context, content.contexts[takeoutContextRequests])
+	m.mergeContext(owner, transient.Context, content.contexts[takeoutContextHistoric])
+
+	if len(content.endPoints) > 0 {
+		m.managerEndPoint.Import(owner, content.endPoints)
+	}
+
+	if webData != nil {
+		if _, err := m.managerWeb.Resolve(owner, webData); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeContext merges the imported context into the one the collection
+// already uses, as the context import does.
+func (m *ManagerTakeout) mergeContext(owner, id string, source *context.Context) {
+	if source == nil {
+		return
+	}
+
+	target, ok := m.managerContext.Find(owner, id)
+	if !ok {
+		return
+	}
+
+	m.managerContext.ImportMerge(owner, dto.FromContext(target), dto.FromContext(source))
+}
+
+// mergeWebData adds the imported entries and namespaces to the current data of
+// the user, the imported ones taking precedence.
+func (m *ManagerTakeout) mergeWebData(owner string, imported *web.WebData) *web.WebData {
+	if imported == nil || (len(imported.Data) == 0 && len(imported.Namespaces) == 0) {
+		return nil
+	}
+
+	current, _ := m.managerWeb.FindByOwner(owner)
+
+	merged := *current
+	merged.Data = maps.Clone(current.Data)
+	if merged.Data == nil {
+		merged.Data = make(map[string]string)
+	}
+	merged.Namespaces = maps.Clone(current.Namespaces)
+	if merged.Namespaces == nil {
+		merged.Namespaces = make(map[string]web.Value)
+	}
+
+	maps.Copy(merged.Data, imported.Data)
+	maps.Copy(merged.Namespaces, imported.Namespaces)
+
+	return &merged
+}
+
+// conflicts lists the kept identifiers the user already holds.
+func (m *ManagerTakeout) conflicts(owner string, content *takeoutContent) []string {
+	conflicts := make([]string, 0)
+
+	for _, v := range content.collections {
+		if _, ok := m.managerCollection.FindDto(owner, v.Id); ok {
+			conflicts = append(conflicts, fmt.Sprintf("collection %s", v.Id))
+		}
+		for _, n := range v.Nodes {
+			if _, _, ok := m.managerRequest.Find(owner, n.Request.Id); ok {
+				conflicts = append(conflicts, fmt.Sprintf("request %s", n.Request.Id))
+			}
+		}
+	}
+
+	for _, requests := range [][]action.Request{content.requests, content.historic} {
+		for _, v := range requests {
+			if _, _, ok := m.managerRequest.Find(owner, v.Id); ok {
+				conflicts = append(conflicts, fmt.Sprintf("request %s", v.Id))
+			}
+		}
+	}
+
+	for _, v := range content.endPoints {
+		if _, ok := m.managerEndPoint.Find(owner, v.Id); ok {
+			conflicts = append(conflicts, fmt.Sprintf("end-point %s", v.Id))
+		}
+	}
+
+	return conflicts
+}
+
+func (t *Takeout) decode() (*takeoutContent, []string, error) {
+	content := &takeoutContent{
+		collections: make([]dto.DtoCollection, 0),
+		requests:    make([]action.Request, 0),
+		historic:    make([]action.Request, 0),
+		contexts:    make(map[string]*context.Context),
+		endPoints:   make([]mock.EndPoint, 0),
+	}
+
+	skipped := make([]string, 0)
+
+	for _, v := range t.entries {
+		var target any
+		switch v.name {
+		case takeoutCollections:
+			target = &content.collections
+		case takeoutRequests:
+			target = &content.requests
+		case takeoutHistoric:
+			target = &content.historic
+		case takeoutContexts:
+			target = &content.contexts
+		case takeoutEndPoints:
+			target = &content.endPoints
+		case takeoutWeb:
+			target = &content.webData
+		default:
+			skipped = append(skipped, v.name)
+			continue
+		}
+
+		if err := json.Unmarshal(v.content, target); err != nil {
+			return nil, nil, TakeoutError{File: v.name, Message: err.Error()}
+		}
+	}
+
+	return content, skipped, nil
+}
+
+// remap clears the identifiers of the content, so new ones are given when it
+// is stored.
+func (c *takeoutContent) remap() {
+	for i := range c.collections {
+		c.collections[i].Id = ""
+		c.collections[i].Context.Id = ""
+		for j := range c.collections[i].Nodes {
+			c.collections[i].Nodes[j].Request.Id = ""
+		}
+	}
+
+	for _, requests := range [][]action.Request{c.requests, c.historic} {
+		for i := range requests {
+			requests[i].Id = ""
+		}
+	}
+
+	for _, v := range c.contexts {
+		if v != nil {
+			v.Id = ""
+		}
+	}
+
+	for i := range c.endPoints {
+		c.endPoints[i].Id = ""
+	}
+}
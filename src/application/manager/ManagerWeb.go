@@ -1,16 +1,44 @@
 package manager
 
 import (
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/Rafael24595/go-api-render/src/domain/revision"
 	"github.com/Rafael24595/go-api-render/src/domain/web"
+	"github.com/Rafael24595/go-log/log"
 )
 
+// WebDataError reports the namespaces of the web data that break their schema
+// or quota.
+type WebDataError struct {
+	Violations []NamespaceViolation
+}
+
+func (e WebDataError) Error() string {
+	if len(e.Violations) == 0 {
+		return "the web data is not valid"
+	}
+	first := e.Violations[0]
+	if first.Namespace == "" {
+		return first.Message
+	}
+	return fmt.Sprintf("the namespace %q is not valid: %s", first.Namespace, first.Message)
+}
+
 type ManagerWeb struct {
-	web web.Repository
+	mu               sync.Mutex
+	web              web.Repository
+	managerNamespace *ManagerNamespace
+	managerRevision  *ManagerRevision
 }
 
-func NewManagerWeb(web web.Repository) *ManagerWeb {
+func NewManagerWeb(web web.Repository, managerNamespace *ManagerNamespace, managerRevision *ManagerRevision) *ManagerWeb {
 	return &ManagerWeb{
-		web: web,
+		web:              web,
+		managerNamespace: managerNamespace,
+		managerRevision:  managerRevision,
 	}
 }
 
@@ -18,26 +46,241 @@ func (m *ManagerWeb) FindByOwner(owner string) (*web.WebData, bool) {
 	if result, ok := m.web.FindByOwner(owner); ok && result != nil {
 		return result, true
 	}
-	return m.Resolve(owner, web.EmptyWebData(owner)), true
+
+	empty := web.EmptyWebData(owner)
+	result, err := m.web.Resolve(owner, empty)
+	if err != nil {
+		log.Errorf("The web data of %q cannot be created: %s", owner, err.Error())
+		return empty, true
+	}
+
+	return result, true
+}
+
+// Exists tells whether the owner has stored web data, without creating the
+// empty one FindByOwner falls back to.
+func (m *ManagerWeb) Exists(owner string) bool {
+	result, ok := m.web.FindByOwner(owner)
+	return ok && result != nil
+}
+
+// ResolveIf replaces the data only if the current version still matches the
+// one the change is based on. Otherwise the current version is returned.
+func (m *ManagerWeb) ResolveIf(owner string, webData *web.WebData, matches func(current *web.WebData) bool) (*web.WebData, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, _ := m.FindByOwner(owner)
+	if !matches(current) {
+		return current, false, nil
+	}
+
+	result, err := m.resolve(owner, webData)
+	return result, true, err
+}
+
+// ModifyIf changes the entries of the data in place of replacing them, only if
+// the current version still matches the one the change is based on. A change
+// that fails leaves the data untouched.
+func (m *ManagerWeb) ModifyIf(owner string, matches func(current *web.WebData) bool, change func(data map[string]string) error) (*web.WebData, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, _ := m.FindByOwner(owner)
+	if !matches(current) {
+		return current, false, nil
+	}
+
+	data := maps.Clone(current.Data)
+	if data == nil {
+		data = make(map[string]string)
+	}
+
+	if err := change(data); err != nil {
+		return current, true, err
+	}
+
+	violations := m.managerNamespace.ValidateData(data)
+	violations = append(violations, m.managerNamespace.ValidateOwner(current, data, current.Namespaces)...)
+	if len(violations) > 0 {
+		return current, true, WebDataError{Violations: violations}
+	}
+
+	previous := *current
+	current.Data = data
+
+	result, err := m.store(owner, previous, current)
+	return result, true, err
 }
 
-func (m *ManagerWeb) Resolve(owner string, webData *web.WebData) *web.WebData {
+// ModifyNamespaceIf changes the value of a single namespace, only if the
+// current version still matches the one the change is based on. A null value
+// removes the namespace.
+func (m *ManagerWeb) ModifyNamespaceIf(owner, name string, matches func(current *web.WebData) bool, change func(value web.Value) (web.Value, error)) (*web.WebData, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, _ := m.FindByOwner(owner)
+	if !matches(current) {
+		return current, false, nil
+	}
+
+	value, err := change(current.Namespaces[name])
+	if err != nil {
+		return current, true, err
+	}
+
+	namespaces := maps.Clone(current.Namespaces)
+	if namespaces == nil {
+		namespaces = make(map[string]web.Value)
+	}
+
+	if value.IsNull() {
+		delete(namespaces, name)
+	} else {
+		if violations := m.managerNamespace.Validate(name, value); len(violations) > 0 {
+			return current, true, WebDataError{Violations: violations}
+		}
+		namespaces[name] = value
+	}
+
+	if violations := m.managerNamespace.ValidateOwner(current, current.Data, namespaces); len(violations) > 0 {
+		return current, true, WebDataError{Violations: violations}
+	}
+
+	previous := *current
+	current.Namespaces = namespaces
+
+	result, err := m.store(owner, previous, current)
+	return result, true, err
+}
+
+// RestoreIf replaces the data with one of its revisions, only if the current
+// version still matches the one the restore is based on. The replaced data
+// becomes a revision in turn, so the restore can be undone.
+func (m *ManagerWeb) RestoreIf(owner string, revision *revision.Revision, matches func(current *web.WebData) bool) (*web.WebData, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, _ := m.FindByOwner(owner)
+	if !matches(current) {
+		return current, false, nil
+	}
+
+	restored := *current
+	restored.Data = maps.Clone(revision.Data)
+	restored.Namespaces = maps.Clone(revision.Namespaces)
+
+	result, err := m.resolve(owner, &restored)
+	return result, true, err
+}
+
+func (m *ManagerWeb) Resolve(owner string, webData *web.WebData) (*web.WebData, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.resolve(owner, webData)
+}
+
+func (m *ManagerWeb) resolve(owner string, webData *web.WebData) (*web.WebData, error) {
 	if owner != "" && webData.Owner != owner {
 		webData, _ = m.FindByOwner(owner)
-		return webData
+		return webData, nil
 	}
 
-	if result, ok := m.web.FindByOwner(owner); ok && result != nil {
-		result.Data = webData.Data
-		webData = result
+	current, ok := m.web.FindByOwner(owner)
+	if !ok {
+		current = nil
+	}
+
+	if err := m.validate(current, webData); err != nil {
+		if current == nil {
+			current, _ = m.FindByOwner(owner)
+		}
+		return current, err
+	}
+
+	if current == nil {
+		result, err := m.web.Resolve(owner, webData)
+		if err != nil {
+			current, _ = m.FindByOwner(owner)
+			return current, err
+		}
+		return result, nil
 	}
-	return m.web.Resolve(owner, webData)
+
+	previous := *current
+	current.Data = webData.Data
+	current.Namespaces = webData.Namespaces
+
+	return m.store(owner, previous, current)
 }
 
-func (m *ManagerWeb) Delete(owner string) (*web.WebData, bool) {
-	webData, ok := m.FindByOwner(owner)
-	if !ok {
-		return nil, false
+// store persists the data and keeps the version it replaces as a revision.
+// When the data cannot be written the previous version is returned.
+func (m *ManagerWeb) store(owner string, previous web.WebData, webData *web.WebData) (*web.WebData, error) {
+	record := m.managerRevision.Prepare(previous, webData)
+
+	result, err := m.web.Resolve(owner, webData)
+	if err != nil {
+		return &previous, err
+	}
+
+	if record {
+		m.managerRevision.Record(previous, *result)
+	}
+
+	return result, nil
+}
+
+// validate checks the namespaces the change modifies, so data stored before
+// a schema was tightened does not block unrelated changes.
+func (m *ManagerWeb) validate(current, webData *web.WebData) error {
+	violations := m.managerNamespace.ValidateData(webData.Data)
+
+	for name, value := range webData.Namespaces {
+		if value.IsNull() {
+			delete(webData.Namespaces, name)
+			continue
+		}
+		if current != nil && current.Namespaces[name] == value {
+			continue
+		}
+		violations = append(violations, m.managerNamespace.Validate(name, value)...)
+	}
+
+	violations = append(violations, m.managerNamespace.ValidateOwner(current, webData.Data, webData.Namespaces)...)
+
+	if len(violations) > 0 {
+		return WebDataError{Violations: violations}
+	}
+
+	return nil
+}
+
+// History returns the previous versions of the data, the newest first.
+func (m *ManagerWeb) History(owner string) []revision.Revision {
+	return m.managerRevision.FindByOwner(owner)
+}
+
+func (m *ManagerWeb) FindRevision(owner string, number int64) (*revision.Revision, bool) {
+	return m.managerRevision.Find(owner, number)
+}
+
+// Close writes the pending changes and releases the repository.
+func (m *ManagerWeb) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.web.Close()
+}
+
+func (m *ManagerWeb) Delete(owner string) (*web.WebData, error) {
+	webData, _ := m.FindByOwner(owner)
+	result, err := m.web.Delete(webData)
+	if err != nil {
+		return nil, err
 	}
-	return m.web.Delete(webData), true
+	m.managerRevision.DeleteAll(owner)
+	return result, nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/Rafael24595/go-api-core/src/commons/local"
 	"github.com/Rafael24595/go-log/log"
 
+	auth "github.com/Rafael24595/go-api-render/src/commons/auth/Jwt.go"
 	"github.com/Rafael24595/go-api-render/src/commons/configuration"
 	"github.com/Rafael24595/go-api-render/src/commons/dependency"
 )
@@ -20,6 +21,7 @@ func Initialize(ctx context.Context) (*configuration.Configuration, *dependency.
 	frontPackage := ReadFrontPackage()
 
 	config := configuration.Initialize(core_conf, kargs, frontPackage)
+	auth.InitializeKeyRing(config)
 	container := dependency.Initialize(config, *core_cont)
 
 	log.Messagef("Display front: %v", config.Front.Enabled)
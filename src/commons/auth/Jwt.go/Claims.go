@@ -2,7 +2,14 @@ package auth
 
 import "github.com/golang-jwt/jwt/v5"
 
+const (
+	TOKEN_ACCESS  = "access"
+	TOKEN_REFRESH = "refresh"
+)
+
 type Claims struct {
 	jwt.RegisteredClaims
 	Username string `json:"username"`
-}
\ No newline at end of file
+	Session  string `json:"sid,omitempty"`
+	Type     string `json:"typ,omitempty"`
+}
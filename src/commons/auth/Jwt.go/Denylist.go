@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+var denylist = struct {
+	mu      sync.RWMutex
+	entries map[string]int64
+}{
+	entries: make(map[string]int64),
+}
+
+// Deny rejects the given token or session identifiers until the expiration
+// time, when every token they could match has already expired on its own.
+func Deny(expires time.Time, ids ...string) {
+	denylist.mu.Lock()
+	defer denylist.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	for k, v := range denylist.entries {
+		if v <= now {
+			delete(denylist.entries, k)
+		}
+	}
+
+	limit := expires.UnixMilli()
+	for _, v := range ids {
+		if v == "" || limit <= now {
+			continue
+		}
+		if current, ok := denylist.entries[v]; !ok || current < limit {
+			denylist.entries[v] = limit
+		}
+	}
+}
+
+func IsDenied(ids ...string) bool {
+	denylist.mu.RLock()
+	defer denylist.mu.RUnlock()
+
+	now := time.Now().UnixMilli()
+	for _, v := range ids {
+		if expires, ok := denylist.entries[v]; ok && expires > now {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+type Jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type Jwks struct {
+	Keys []Jwk `json:"keys"`
+}
+
+// Jwks returns the public part of every key that is still accepted for
+// verification, so external services can validate the issued tokens.
+func (k *KeyRing) Jwks() Jwks {
+	jwks := Jwks{
+		Keys: make([]Jwk, 0),
+	}
+
+	if k.isSymmetric() {
+		return jwks
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, v := range k.keys {
+		jwk := Jwk{
+			Use: "sig",
+			Alg: k.method.Alg(),
+			Kid: v.kid,
+		}
+
+		switch public := v.private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = encodeSegment(public.N.Bytes())
+			jwk.E = encodeSegment(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = encodeSegment(public)
+		default:
+			continue
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -1,46 +1,60 @@
 package auth
 
 import (
+	"errors"
 	"time"
 
-	"github.com/Rafael24595/go-api-render/src/commons/configuration"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateJWT(username string) (string, error) {
-	return generateJWT(username, 30 * time.Minute)
-}
+var ErrTokenType = errors.New("the token is not of the expected type")
 
-func GenerateRefreshJWT(username string) (string, error) {
-	return generateJWT(username, 7 * 24 * time.Hour)
+func GenerateJWT(username, session, id string, lifetime time.Duration) (string, error) {
+	return generateJWT(TOKEN_ACCESS, username, session, id, lifetime)
 }
 
-func generateJWT(username string, duration time.Duration) (string, error) {
-	secret := configuration.Instance().Secret()
+func GenerateRefreshJWT(username, session, id string, lifetime time.Duration) (string, error) {
+	return generateJWT(TOKEN_REFRESH, username, session, id, lifetime)
+}
 
+func generateJWT(kind, username, session, id string, duration time.Duration) (string, error) {
 	expirationTime := time.Now().Add(duration)
 	claims := &Claims{
 		Username: username,
+		Session:  session,
+		Type:     kind,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
 	}
 
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).
-		SignedString(secret)
+	return InstanceKeyRing().sign(claims)
 }
 
-func ValidateJWT(tokenString string) (*Claims, error) {
-	secret := configuration.Instance().Secret()
+// ValidateJWT verifies the token and checks it is of the given kind, so a
+// refresh token is never accepted in place of an access token. Tokens issued
+// before the kind was recorded carry none and are left to the session checks.
+func ValidateJWT(tokenString, kind string) (*Claims, error) {
+	keyRing := InstanceKeyRing()
 
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
-		return secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, keyRing.verification,
+		jwt.WithValidMethods([]string{keyRing.Algorithm()}))
 
 	if err != nil || !token.Valid {
 		return claims, err
 	}
-	
+
+	if claims.Type != "" && claims.Type != kind {
+		return claims, ErrTokenType
+	}
+
 	return claims, nil
-}
\ No newline at end of file
+}
+
+// IsExpired reports whether the validation failed only because the token
+// has expired, which means its signature and claims can still be trusted.
+func IsExpired(err error) bool {
+	return errors.Is(err, jwt.ErrTokenExpired)
+}
@@ -0,0 +1,361 @@
+package auth
+
+import (
+	"cmp"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Rafael24595/go-api-core/src/commons/local"
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-log/log"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const KEY_RING_CATEGORY = "KEY_RING"
+
+const keyExtension = ".pem"
+const keyPemType = "PRIVATE KEY"
+const rsaKeySize = 2048
+
+var (
+	ring     *KeyRing
+	ringOnce sync.Once
+)
+
+type signingKey struct {
+	kid     string
+	created int64
+	private crypto.Signer
+}
+
+type KeyRing struct {
+	mu        sync.RWMutex
+	method    jwt.SigningMethod
+	path      string
+	rotation  time.Duration
+	retention int
+	keys      []*signingKey
+}
+
+func InitializeKeyRing(config configuration.Configuration) *KeyRing {
+	ringOnce.Do(func() {
+		instance := &KeyRing{
+			method:    jwt.GetSigningMethod(config.JwtAlgorithm()),
+			path:      config.JwtKeys(),
+			rotation:  config.JwtRotation(),
+			retention: config.JwtRetention(),
+			keys:      make([]*signingKey, 0),
+		}
+
+		if instance.method == nil {
+			local.Panicf("The JWT algorithm %q is not supported", config.JwtAlgorithm())
+		}
+
+		if instance.isSymmetric() {
+			log.Customf(KEY_RING_CATEGORY, "Tokens are signed with the shared secret; no public keys will be published.")
+			ring = instance
+			return
+		}
+
+		if err := instance.load(); err != nil {
+			local.Panicf("Cannot load the JWT signing keys: %v", err)
+		}
+
+		if err := instance.rotate(false); err != nil {
+			local.Panicf("Cannot generate the JWT signing key: %v", err)
+		}
+
+		go instance.watch(config)
+
+		ring = instance
+	})
+
+	if ring == nil {
+		local.Panics("The key ring is not initialized properly")
+	}
+
+	return ring
+}
+
+func InstanceKeyRing() *KeyRing {
+	if ring == nil {
+		local.Panics("The key ring is not initialized yet")
+	}
+	return ring
+}
+
+func (k *KeyRing) Algorithm() string {
+	return k.method.Alg()
+}
+
+func (k *KeyRing) isSymmetric() bool {
+	return k.method == jwt.SigningMethodHS256
+}
+
+func (k *KeyRing) watch(config configuration.Configuration) {
+	if k.rotation <= 0 {
+		log.Customf(KEY_RING_CATEGORY, "Key rotation is disabled.")
+		return
+	}
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-config.Signal.Done():
+			return
+		case <-ticker.C:
+			if err := k.rotate(false); err != nil {
+				log.Custome(KEY_RING_CATEGORY, err)
+			}
+		}
+	}
+}
+
+// Rotate forces the generation of a new signing key. The previous keys
+// remain available for verification until the retention limit is reached.
+func (k *KeyRing) Rotate() error {
+	if k.isSymmetric() {
+		return errors.New("the shared secret cannot be rotated from the key ring")
+	}
+	return k.rotate(true)
+}
+
+func (k *KeyRing) rotate(force bool) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !force && len(k.keys) > 0 {
+		age := time.Since(time.UnixMilli(k.keys[0].created))
+		if k.rotation <= 0 || age < k.rotation {
+			return nil
+		}
+	}
+
+	key, err := k.generate()
+	if err != nil {
+		return err
+	}
+
+	if err := k.store(key); err != nil {
+		return err
+	}
+
+	k.keys = append([]*signingKey{key}, k.keys...)
+	log.Customf(KEY_RING_CATEGORY, "New signing key %q has been generated.", key.kid)
+
+	k.prune()
+
+	return nil
+}
+
+func (k *KeyRing) prune() {
+	limit := k.retention + 1
+	if len(k.keys) <= limit {
+		return
+	}
+
+	for _, v := range k.keys[limit:] {
+		file := filepath.Join(k.path, v.kid+keyExtension)
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			log.Custome(KEY_RING_CATEGORY, err)
+			continue
+		}
+		log.Customf(KEY_RING_CATEGORY, "Signing key %q has been retired.", v.kid)
+	}
+
+	k.keys = k.keys[:limit]
+}
+
+func (k *KeyRing) generate() (*signingKey, error) {
+	var private crypto.Signer
+	var err error
+
+	switch k.method {
+	case jwt.SigningMethodRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case jwt.SigningMethodEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("the algorithm %q cannot generate signing keys", k.method.Alg())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	created := time.Now().UnixMilli()
+	return &signingKey{
+		kid:     fmt.Sprintf("%d-%s", created, hex.EncodeToString(suffix)),
+		created: created,
+		private: private,
+	}, nil
+}
+
+func (k *KeyRing) store(key *signingKey) error {
+	if err := os.MkdirAll(k.path, 0700); err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key.private)
+	if err != nil {
+		return err
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{
+		Type:  keyPemType,
+		Bytes: der,
+	})
+
+	file := filepath.Join(k.path, key.kid+keyExtension)
+	return os.WriteFile(file, data, 0600)
+}
+
+func (k *KeyRing) load() error {
+	entries, err := os.ReadDir(k.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*signingKey, 0)
+	for _, v := range entries {
+		if v.IsDir() || !strings.HasSuffix(v.Name(), keyExtension) {
+			continue
+		}
+
+		key, err := k.read(filepath.Join(k.path, v.Name()))
+		if err != nil {
+			log.Customf(KEY_RING_CATEGORY, "Signing key %q ignored: %s", v.Name(), err.Error())
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b *signingKey) int {
+		return cmp.Compare(b.created, a.created)
+	})
+
+	k.keys = keys
+	k.prune()
+
+	return nil
+}
+
+func (k *KeyRing) read(file string) (*signingKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != keyPemType {
+		return nil, errors.New("invalid PEM block")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var private crypto.Signer
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.method != jwt.SigningMethodRS256 {
+			return nil, errors.New("the RSA key does not match the configured algorithm")
+		}
+		private = key
+	case ed25519.PrivateKey:
+		if k.method != jwt.SigningMethodEdDSA {
+			return nil, errors.New("the Ed25519 key does not match the configured algorithm")
+		}
+		private = key
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+
+	kid := strings.TrimSuffix(filepath.Base(file), keyExtension)
+	prefix, _, _ := strings.Cut(kid, "-")
+
+	created, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key identifier %q", kid)
+	}
+
+	return &signingKey{
+		kid:     kid,
+		created: created,
+		private: private,
+	}, nil
+}
+
+func (k *KeyRing) sign(claims jwt.Claims) (string, error) {
+	if k.isSymmetric() {
+		secret := configuration.Instance().Secret()
+		return jwt.NewWithClaims(k.method, claims).
+			SignedString(secret)
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if len(k.keys) == 0 {
+		return "", errors.New("there is no active signing key")
+	}
+
+	active := k.keys[0]
+
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = active.kid
+
+	return token.SignedString(active.private)
+}
+
+func (k *KeyRing) verification(token *jwt.Token) (any, error) {
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+	}
+
+	if k.isSymmetric() {
+		return configuration.Instance().Secret(), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("the token does not define a key identifier")
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, v := range k.keys {
+		if v.kid == kid {
+			return v.private.Public(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown key identifier %q", kid)
+}
@@ -0,0 +1,34 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+)
+
+const defaultAuditLimit = 100000
+const defaultAuditAge = 90
+
+// Audit limits the security events kept, by count and by age; the oldest ones
+// are pruned first. A limit or an age of 0 disables that bound.
+type Audit struct {
+	Limit  int
+	MaxAge time.Duration
+}
+
+func auditArgs(kargs map[string]utils.Argument) Audit {
+	limit := kargs["GAR_AUDIT_LIMIT"].Intd(defaultAuditLimit)
+	if limit < 0 {
+		limit = 0
+	}
+
+	age := kargs["GAR_AUDIT_AGE"].Intd(defaultAuditAge)
+	if age < 0 {
+		age = 0
+	}
+
+	return Audit{
+		Limit:  limit,
+		MaxAge: time.Duration(age) * 24 * time.Hour,
+	}
+}
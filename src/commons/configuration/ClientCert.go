@@ -0,0 +1,130 @@
+package configuration
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"strings"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+	"github.com/Rafael24595/go-log/log"
+)
+
+const (
+	CLIENT_CERT_OFF      = "off"
+	CLIENT_CERT_OPTIONAL = "optional"
+	CLIENT_CERT_REQUIRE  = "require"
+)
+
+const (
+	CLIENT_CERT_USER_CN    = "cn"
+	CLIENT_CERT_USER_EMAIL = "email"
+	CLIENT_CERT_USER_DNS   = "dns"
+	CLIENT_CERT_USER_URI   = "uri"
+)
+
+// ClientCert defines the mutual TLS authentication. The certificates signed by
+// the configured CA bundle authenticate the user named by the chosen field.
+type ClientCert struct {
+	Mode string
+	CA   string
+	User string
+	pool *x509.CertPool
+}
+
+func clientCertArgs(kargs map[string]utils.Argument, tls bool) ClientCert {
+	disabled := ClientCert{
+		Mode: CLIENT_CERT_OFF,
+	}
+
+	mode := strings.ToLower(kargs["GAR_SERVER_TLS_CLIENT"].String())
+	switch mode {
+	case "", CLIENT_CERT_OFF:
+		return disabled
+	case CLIENT_CERT_OPTIONAL, CLIENT_CERT_REQUIRE:
+	default:
+		log.Warningf("Client certificate mode '%s' is not supported; client certificates are disabled", mode)
+		return disabled
+	}
+
+	if !tls {
+		log.Warning("Client certificates require TLS; client certificates are disabled")
+		return disabled
+	}
+
+	user := strings.ToLower(kargs["GAR_SERVER_TLS_CLIENT_USER"].String())
+	switch user {
+	case "":
+		user = CLIENT_CERT_USER_CN
+	case CLIENT_CERT_USER_CN, CLIENT_CERT_USER_EMAIL, CLIENT_CERT_USER_DNS, CLIENT_CERT_USER_URI:
+	default:
+		log.Warningf("Client certificate user field '%s' is not supported; using the subject common name", user)
+		user = CLIENT_CERT_USER_CN
+	}
+
+	ca := kargs["GAR_SERVER_TLS_CLIENT_CA"].String()
+	bundle, err := os.ReadFile(ca)
+	if err != nil {
+		log.Warningf("Client certificate CA bundle '%s' cannot be read; client certificates are disabled", ca)
+		return disabled
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		log.Warningf("Client certificate CA bundle '%s' has no valid certificates; client certificates are disabled", ca)
+		return disabled
+	}
+
+	log.Messagef("Client certificates are enabled in %s mode", mode)
+
+	return ClientCert{
+		Mode: mode,
+		CA:   ca,
+		User: user,
+		pool: pool,
+	}
+}
+
+func (c ClientCert) Enabled() bool {
+	return c.Mode != CLIENT_CERT_OFF && c.pool != nil
+}
+
+// TLSConfig requests the client certificates and verifies them against the
+// CA bundle. In optional mode the clients without a certificate are still
+// served and may authenticate by other means.
+func (c ClientCert) TLSConfig() *tls.Config {
+	auth := tls.VerifyClientCertIfGiven
+	if c.Mode == CLIENT_CERT_REQUIRE {
+		auth = tls.RequireAndVerifyClientCert
+	}
+
+	return &tls.Config{
+		ClientAuth: auth,
+		ClientCAs:  c.pool,
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+// Username returns the user named by a verified client certificate.
+func (c ClientCert) Username(cert *x509.Certificate) (string, bool) {
+	switch c.User {
+	case CLIENT_CERT_USER_EMAIL:
+		return firstValue(cert.EmailAddresses)
+	case CLIENT_CERT_USER_DNS:
+		return firstValue(cert.DNSNames)
+	case CLIENT_CERT_USER_URI:
+		if len(cert.URIs) == 0 {
+			return "", false
+		}
+		return cert.URIs[0].String(), true
+	default:
+		return cert.Subject.CommonName, cert.Subject.CommonName != ""
+	}
+}
+
+func firstValue(values []string) (string, bool) {
+	if len(values) == 0 || values[0] == "" {
+		return "", false
+	}
+	return values[0], true
+}
@@ -3,6 +3,7 @@ package configuration
 import (
 	"os"
 	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +18,13 @@ const defaultPort = 8080
 const defaultCert = "./cert/cert.pem"
 const defaultKey = "./cert/key.pem"
 
+const defaultJwtAlgorithm = "HS256"
+const defaultJwtKeys = "./db/keys"
+const defaultJwtRotation = 30 * 24
+const defaultJwtRetention = 2
+
+const defaultTotpIssuer = "go-api"
+
 const devRelease = `^(v\d.*\d*.\d*)-(dev.\d*)$`
 
 var (
@@ -26,17 +34,36 @@ var (
 
 type Configuration struct {
 	core_configuration.Configuration
-	Release         *core_configuration.Release
-	Front           FrontPackage
-	debug           bool
-	port            int
-	onlyTLS         bool
-	portTLS         int
-	certTLS         string
-	keyTLS          string
-	enableSecrets   bool
-	enableUserToken bool
-	WebDataLimit    int64
+	Release           *core_configuration.Release
+	Front             FrontPackage
+	debug             bool
+	port              int
+	onlyTLS           bool
+	portTLS           int
+	certTLS           string
+	keyTLS            string
+	clientCert        ClientCert
+	enableSecrets     bool
+	enableUserToken   bool
+	jwtAlgorithm      string
+	jwtKeys           string
+	jwtRotation       int
+	jwtRetention      int
+	oidc              Oidc
+	totpIssuer        string
+	totpAdmins        bool
+	lockout           Lockout
+	password          Password
+	session           Session
+	cookie            Cookie
+	cors              Cors
+	storage           Storage
+	webHistory        WebHistory
+	deletion          Deletion
+	audit             Audit
+	WebDataLimit      int64
+	WebDataKeyLimit   int64
+	WebDataOwnerLimit int64
 }
 
 func Initialize(core *core_configuration.Configuration, kargs map[string]utils.Argument, frontPackage *FrontPackage) Configuration {
@@ -57,6 +84,8 @@ func Initialize(core *core_configuration.Configuration, kargs map[string]utils.A
 
 		portTLS, certTLS, keyTLS, onlyTLS := tlsArgs(kargs)
 
+		clientCert := clientCertArgs(kargs, portTLS != 0 && certTLS != "" && keyTLS != "")
+
 		frontPackage.Enabled = front
 		if !front {
 			frontPackage.Name = ""
@@ -66,20 +95,73 @@ func Initialize(core *core_configuration.Configuration, kargs map[string]utils.A
 		enableSecrets := kargs["GAR_MISC_SECRETS"].Boold(false)
 		enableUserToken := kargs["GAR_AUTH_USER_TOKEN"].Boold(false)
 
+		jwtAlgorithm, jwtKeys, jwtRotation, jwtRetention := jwtArgs(kargs)
+
+		oidc := oidcArgs(kargs)
+		if oidc.Enabled() {
+			log.Messagef("OpenID Connect login is enabled with the issuer %s", oidc.Issuer)
+		}
+
+		totpIssuer := kargs["GAR_AUTH_2FA_ISSUER"].String()
+		if totpIssuer == "" {
+			totpIssuer = defaultTotpIssuer
+		}
+
+		totpAdmins := kargs["GAR_AUTH_2FA_ADMINS"].Boold(false)
+
+		lockout := lockoutArgs(kargs)
+
+		password := passwordArgs(kargs)
+
+		session := sessionArgs(kargs)
+
+		cookie := cookieArgs(kargs, portTLS != 0 && certTLS != "" && keyTLS != "")
+
+		cors := corsArgs(kargs)
+
+		storage := storageArgs(kargs)
+
+		webHistory := webHistoryArgs(kargs)
+
+		deletion := deletionArgs(kargs)
+
+		audit := auditArgs(kargs)
+
 		webDataLimit := kargs["GAR_WEB_DATA_LIMIT"].Int64d(0)
+		webDataKeyLimit := kargs["GAR_WEB_DATA_KEY_LIMIT"].Int64d(0)
+		webDataOwnerLimit := kargs["GAR_WEB_DATA_OWNER_LIMIT"].Int64d(1048576)
 
 		instance = &Configuration{
-			Configuration:   *core,
-			Front:           *frontPackage,
-			debug:           debug,
-			port:            port,
-			onlyTLS:         onlyTLS,
-			portTLS:         portTLS,
-			certTLS:         certTLS,
-			keyTLS:          keyTLS,
-			enableSecrets:   enableSecrets,
-			enableUserToken: enableUserToken,
-			WebDataLimit:    webDataLimit,
+			Configuration:     *core,
+			Front:             *frontPackage,
+			debug:             debug,
+			port:              port,
+			onlyTLS:           onlyTLS,
+			portTLS:           portTLS,
+			certTLS:           certTLS,
+			keyTLS:            keyTLS,
+			clientCert:        clientCert,
+			enableSecrets:     enableSecrets,
+			enableUserToken:   enableUserToken,
+			jwtAlgorithm:      jwtAlgorithm,
+			jwtKeys:           jwtKeys,
+			jwtRotation:       jwtRotation,
+			jwtRetention:      jwtRetention,
+			oidc:              oidc,
+			totpIssuer:        totpIssuer,
+			totpAdmins:        totpAdmins,
+			lockout:           lockout,
+			password:          password,
+			session:           session,
+			cookie:            cookie,
+			cors:              cors,
+			storage:           storage,
+			webHistory:        webHistory,
+			deletion:          deletion,
+			audit:             audit,
+			WebDataLimit:      webDataLimit,
+			WebDataKeyLimit:   webDataKeyLimit,
+			WebDataOwnerLimit: webDataOwnerLimit,
 		}
 
 		go instance.originLastVersion(kargs)
@@ -133,6 +215,37 @@ func tlsArgs(kargs map[string]utils.Argument) (int, string, string, bool) {
 	return portTLS, certTLS, keyTLS, onlyTLS
 }
 
+func jwtArgs(kargs map[string]utils.Argument) (string, string, int, int) {
+	algorithm := strings.ToUpper(kargs["GAR_AUTH_JWT_ALGORITHM"].String())
+	switch algorithm {
+	case "":
+		algorithm = defaultJwtAlgorithm
+	case "EDDSA":
+		algorithm = "EdDSA"
+	case "HS256", "RS256":
+	default:
+		log.Warningf("JWT algorithm '%s' is not supported; using default algorithm %s", algorithm, defaultJwtAlgorithm)
+		algorithm = defaultJwtAlgorithm
+	}
+
+	keys := kargs["GAR_AUTH_JWT_KEYS"].String()
+	if keys == "" {
+		keys = defaultJwtKeys
+	}
+
+	rotation := kargs["GAR_AUTH_JWT_ROTATION"].Intd(defaultJwtRotation)
+	if rotation < 0 {
+		rotation = 0
+	}
+
+	retention := kargs["GAR_AUTH_JWT_RETENTION"].Intd(defaultJwtRetention)
+	if retention < 0 {
+		retention = 0
+	}
+
+	return algorithm, keys, rotation, retention
+}
+
 func (c *Configuration) originLastVersion(kargs map[string]utils.Argument) {
 	releaseTime, ok := kargs["GAR_RELEASE_TIME"].Int()
 	if !ok || releaseTime < 1 {
@@ -207,6 +320,10 @@ func (c Configuration) KeyTLS() string {
 	return c.keyTLS
 }
 
+func (c Configuration) ClientCert() ClientCert {
+	return c.clientCert
+}
+
 func (c Configuration) EnableSecrets() bool {
 	return c.enableSecrets
 }
@@ -215,6 +332,70 @@ func (c Configuration) EnableUserToken() bool {
 	return c.enableUserToken
 }
 
+func (c Configuration) JwtAlgorithm() string {
+	return c.jwtAlgorithm
+}
+
+func (c Configuration) JwtKeys() string {
+	return c.jwtKeys
+}
+
+func (c Configuration) JwtRotation() time.Duration {
+	return time.Duration(c.jwtRotation) * time.Hour
+}
+
+func (c Configuration) JwtRetention() int {
+	return c.jwtRetention
+}
+
+func (c Configuration) Oidc() Oidc {
+	return c.oidc
+}
+
+func (c Configuration) TotpIssuer() string {
+	return c.totpIssuer
+}
+
+func (c Configuration) TotpAdmins() bool {
+	return c.totpAdmins
+}
+
+func (c Configuration) Lockout() Lockout {
+	return c.lockout
+}
+
+func (c Configuration) Password() Password {
+	return c.password
+}
+
+func (c Configuration) Session() Session {
+	return c.session
+}
+
+func (c Configuration) Cookie() Cookie {
+	return c.cookie
+}
+
+func (c Configuration) Cors() Cors {
+	return c.cors
+}
+
+func (c Configuration) Storage() Storage {
+	return c.storage
+}
+
+func (c Configuration) WebHistory() WebHistory {
+	return c.webHistory
+}
+
+func (c Configuration) Deletion() Deletion {
+	return c.deletion
+}
+
+func (c Configuration) Audit() Audit {
+	return c.audit
+}
+
 func (c Configuration) DefaultProtocol() string {
 	if c.EnableTLS() {
 		return "https"
@@ -0,0 +1,78 @@
+package configuration
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+	"github.com/Rafael24595/go-log/log"
+)
+
+const (
+	hostPrefix   = "__Host-"
+	securePrefix = "__Secure-"
+)
+
+type Cookie struct {
+	Secure   bool
+	Domain   string
+	SameSite http.SameSite
+	Prefix   bool
+	Csrf     bool
+}
+
+func cookieArgs(kargs map[string]utils.Argument, tls bool) Cookie {
+	secure := tls
+	switch strings.ToLower(kargs["GAR_COOKIE_SECURE"].String()) {
+	case "", "auto":
+	case "true":
+		secure = true
+	case "false":
+		secure = false
+	default:
+		log.Warningf("Cookie secure mode '%s' is not supported; using auto mode", kargs["GAR_COOKIE_SECURE"].String())
+	}
+
+	sameSite := http.SameSiteLaxMode
+	switch strings.ToLower(kargs["GAR_COOKIE_SAMESITE"].String()) {
+	case "", "lax":
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	default:
+		log.Warningf("Cookie SameSite mode '%s' is not supported; using Lax mode", kargs["GAR_COOKIE_SAMESITE"].String())
+	}
+
+	if sameSite == http.SameSiteNoneMode && !secure {
+		log.Warning("Cookie SameSite mode None requires secure cookies; using Lax mode")
+		sameSite = http.SameSiteLaxMode
+	}
+
+	prefix := kargs["GAR_COOKIE_PREFIX"].Boold(false)
+	if prefix && !secure {
+		log.Warning("Cookie prefixes require secure cookies; prefixes will not be applied")
+		prefix = false
+	}
+
+	return Cookie{
+		Secure:   secure,
+		Domain:   kargs["GAR_COOKIE_DOMAIN"].String(),
+		SameSite: sameSite,
+		Prefix:   prefix,
+		Csrf:     kargs["GAR_AUTH_CSRF"].Boold(true),
+	}
+}
+
+// Name returns the cookie name with the strongest prefix its attributes
+// allow: __Host- for host-only cookies scoped to the root path and __Secure-
+// for any other cookie.
+func (c Cookie) Name(name, path string) string {
+	if !c.Prefix {
+		return name
+	}
+	if c.Domain == "" && path == "/" {
+		return hostPrefix + name
+	}
+	return securePrefix + name
+}
@@ -0,0 +1,38 @@
+package configuration
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+	"github.com/Rafael24595/go-log/log"
+)
+
+// Cors lists the origins allowed to call the API from a browser with the
+// credentials of the user. No origin is allowed by default.
+type Cors struct {
+	Origins []string
+}
+
+func corsArgs(kargs map[string]utils.Argument) Cors {
+	origins := make([]string, 0)
+	for _, v := range strings.Split(kargs["GAR_CORS_ORIGINS"].String(), ",") {
+		origin := strings.TrimSuffix(strings.TrimSpace(v), "/")
+		if origin == "" {
+			continue
+		}
+		if origin == "*" {
+			log.Warning("The wildcard CORS origin cannot be combined with credentials; it will be ignored")
+			continue
+		}
+		origins = append(origins, origin)
+	}
+
+	return Cors{
+		Origins: origins,
+	}
+}
+
+func (c Cors) Allows(origin string) bool {
+	return origin != "" && slices.Contains(c.Origins, origin)
+}
@@ -0,0 +1,29 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+)
+
+// Deletion sets how long the data of a deleted user is kept before it is
+// purged. During the grace period the account stays disabled and the deletion
+// can be cancelled. A grace of 0 purges the data at once.
+type Deletion struct {
+	Grace time.Duration
+}
+
+func deletionArgs(kargs map[string]utils.Argument) Deletion {
+	grace := kargs["GAR_USER_DELETION_GRACE"].Intd(0)
+	if grace < 0 {
+		grace = 0
+	}
+
+	return Deletion{
+		Grace: time.Duration(grace) * time.Hour,
+	}
+}
+
+func (d Deletion) Enabled() bool {
+	return d.Grace > 0
+}
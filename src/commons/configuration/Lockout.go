@@ -0,0 +1,56 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+)
+
+const defaultLockoutThreshold = 5
+const defaultLockoutAddressThreshold = 20
+const defaultLockoutBase = 1
+const defaultLockoutMax = 15 * 60
+const defaultLockoutWindow = 15
+
+type Lockout struct {
+	Threshold        int
+	AddressThreshold int
+	Base             time.Duration
+	Max              time.Duration
+	Window           time.Duration
+}
+
+func lockoutArgs(kargs map[string]utils.Argument) Lockout {
+	threshold := kargs["GAR_AUTH_LOCKOUT_THRESHOLD"].Intd(defaultLockoutThreshold)
+	if threshold < 1 {
+		threshold = defaultLockoutThreshold
+	}
+
+	addressThreshold := kargs["GAR_AUTH_LOCKOUT_ADDRESS_THRESHOLD"].Intd(defaultLockoutAddressThreshold)
+	if addressThreshold < 1 {
+		addressThreshold = defaultLockoutAddressThreshold
+	}
+
+	base := kargs["GAR_AUTH_LOCKOUT_BASE"].Intd(defaultLockoutBase)
+	if base < 1 {
+		base = defaultLockoutBase
+	}
+
+	max := kargs["GAR_AUTH_LOCKOUT_MAX"].Intd(defaultLockoutMax)
+	if max < base {
+		max = base
+	}
+
+	window := kargs["GAR_AUTH_LOCKOUT_WINDOW"].Intd(defaultLockoutWindow)
+	if window < 1 {
+		window = defaultLockoutWindow
+	}
+
+	return Lockout{
+		Threshold:        threshold,
+		AddressThreshold: addressThreshold,
+		Base:             time.Duration(base) * time.Second,
+		Max:              time.Duration(max) * time.Second,
+		Window:           time.Duration(window) * time.Minute,
+	}
+}
@@ -0,0 +1,69 @@
+package configuration
+
+import (
+	"strings"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+)
+
+const defaultOidcScopes = "openid profile email"
+const defaultOidcUsernameClaim = "preferred_username"
+const defaultOidcGroupsClaim = "groups"
+const defaultOidcProvisioner = "admin"
+
+type Oidc struct {
+	Issuer        string
+	ClientId      string
+	ClientSecret  string
+	Redirect      string
+	Landing       string
+	Scopes        []string
+	UsernameClaim string
+	GroupsClaim   string
+	AdminGroup    string
+	Provisioner   string
+}
+
+func oidcArgs(kargs map[string]utils.Argument) Oidc {
+	scopes := kargs["GAR_OIDC_SCOPES"].String()
+	if scopes == "" {
+		scopes = defaultOidcScopes
+	}
+
+	usernameClaim := kargs["GAR_OIDC_USERNAME_CLAIM"].String()
+	if usernameClaim == "" {
+		usernameClaim = defaultOidcUsernameClaim
+	}
+
+	groupsClaim := kargs["GAR_OIDC_GROUPS_CLAIM"].String()
+	if groupsClaim == "" {
+		groupsClaim = defaultOidcGroupsClaim
+	}
+
+	provisioner := kargs["GAR_OIDC_PROVISIONER"].String()
+	if provisioner == "" {
+		provisioner = defaultOidcProvisioner
+	}
+
+	landing := kargs["GAR_OIDC_LANDING"].String()
+	if landing == "" {
+		landing = "/"
+	}
+
+	return Oidc{
+		Issuer:        strings.TrimSuffix(kargs["GAR_OIDC_ISSUER"].String(), "/"),
+		ClientId:      kargs["GAR_OIDC_CLIENT_ID"].String(),
+		ClientSecret:  kargs["GAR_OIDC_CLIENT_SECRET"].String(),
+		Redirect:      kargs["GAR_OIDC_REDIRECT"].String(),
+		Landing:       landing,
+		Scopes:        strings.Fields(scopes),
+		UsernameClaim: usernameClaim,
+		GroupsClaim:   groupsClaim,
+		AdminGroup:    kargs["GAR_OIDC_ADMIN_GROUP"].String(),
+		Provisioner:   provisioner,
+	}
+}
+
+func (o Oidc) Enabled() bool {
+	return o.Issuer != "" && o.ClientId != "" && o.Redirect != ""
+}
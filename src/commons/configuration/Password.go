@@ -0,0 +1,82 @@
+package configuration
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+	"github.com/Rafael24595/go-log/log"
+)
+
+const defaultPasswordMinLength = 8
+const maxPasswordClasses = 4
+
+type Password struct {
+	MinLength int
+	Classes   int
+	Banned    []string
+	History   int
+	MaxAge    time.Duration
+}
+
+func passwordArgs(kargs map[string]utils.Argument) Password {
+	minLength := kargs["GAR_AUTH_PASSWORD_MIN_LENGTH"].Intd(defaultPasswordMinLength)
+	if minLength < 0 {
+		minLength = defaultPasswordMinLength
+	}
+
+	classes := kargs["GAR_AUTH_PASSWORD_CLASSES"].Intd(0)
+	if classes < 0 {
+		classes = 0
+	}
+	if classes > maxPasswordClasses {
+		log.Warningf("Password policy cannot require more than %d character classes; requiring all of them", maxPasswordClasses)
+		classes = maxPasswordClasses
+	}
+
+	history := kargs["GAR_AUTH_PASSWORD_HISTORY"].Intd(0)
+	if history < 0 {
+		history = 0
+	}
+
+	maxAge := kargs["GAR_AUTH_PASSWORD_MAX_AGE"].Intd(0)
+	if maxAge < 0 {
+		maxAge = 0
+	}
+
+	return Password{
+		MinLength: minLength,
+		Classes:   classes,
+		Banned:    bannedPasswords(kargs["GAR_AUTH_PASSWORD_BANNED"].String()),
+		History:   history,
+		MaxAge:    time.Duration(maxAge) * 24 * time.Hour,
+	}
+}
+
+// bannedPasswords reads the banned password list, one password per line.
+// Blank lines and lines starting with # are ignored.
+func bannedPasswords(path string) []string {
+	if path == "" {
+		return make([]string, 0)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		log.Warningf("Banned password list '%s' cannot be read; no password will be banned: %s", path, err.Error())
+		return make([]string, 0)
+	}
+
+	banned := make([]string, 0)
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		banned = append(banned, strings.ToLower(line))
+	}
+
+	log.Messagef("%d passwords have been banned from '%s'", len(banned), path)
+
+	return banned
+}
@@ -0,0 +1,82 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+	"github.com/Rafael24595/go-log/log"
+)
+
+const defaultSessionAccess = 30
+const defaultSessionIdle = 7 * 24 * 60
+const defaultSessionRememberIdle = 30 * 24 * 60
+
+// Session holds the lifetimes of the login sessions. The idle lifetime slides
+// with the activity of the session and the absolute one, when defined, ends
+// it regardless of the activity. Remembered sessions use their own policy.
+type Session struct {
+	Access   time.Duration
+	Idle     time.Duration
+	Absolute time.Duration
+	Remember SessionPolicy
+}
+
+type SessionPolicy struct {
+	Idle     time.Duration
+	Absolute time.Duration
+}
+
+func sessionArgs(kargs map[string]utils.Argument) Session {
+	access := kargs["GAR_SESSION_ACCESS"].Intd(defaultSessionAccess)
+	if access < 1 {
+		access = defaultSessionAccess
+	}
+
+	idle := kargs["GAR_SESSION_IDLE"].Intd(defaultSessionIdle)
+	if idle < access {
+		log.Warningf("Session idle lifetime cannot be shorter than the access lifetime; using %d minutes", access)
+		idle = access
+	}
+
+	absolute := sessionAbsolute(kargs, "GAR_SESSION_ABSOLUTE", idle)
+
+	rememberIdle := kargs["GAR_SESSION_REMEMBER_IDLE"].Intd(defaultSessionRememberIdle)
+	if rememberIdle < idle {
+		rememberIdle = idle
+	}
+
+	rememberAbsolute := sessionAbsolute(kargs, "GAR_SESSION_REMEMBER_ABSOLUTE", rememberIdle)
+
+	return Session{
+		Access:   time.Duration(access) * time.Minute,
+		Idle:     time.Duration(idle) * time.Minute,
+		Absolute: time.Duration(absolute) * time.Minute,
+		Remember: SessionPolicy{
+			Idle:     time.Duration(rememberIdle) * time.Minute,
+			Absolute: time.Duration(rememberAbsolute) * time.Minute,
+		},
+	}
+}
+
+func sessionAbsolute(kargs map[string]utils.Argument, key string, idle int) int {
+	absolute := kargs[key].Intd(0)
+	if absolute < 0 {
+		return 0
+	}
+	if absolute > 0 && absolute < idle {
+		log.Warningf("%s cannot be shorter than the idle lifetime; using %d minutes", key, idle)
+		return idle
+	}
+	return absolute
+}
+
+// Policy returns the idle and absolute lifetimes of a session.
+func (s Session) Policy(remember bool) SessionPolicy {
+	if remember {
+		return s.Remember
+	}
+	return SessionPolicy{
+		Idle:     s.Idle,
+		Absolute: s.Absolute,
+	}
+}
@@ -0,0 +1,48 @@
+package configuration
+
+import (
+	"strings"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+	"github.com/Rafael24595/go-log/log"
+)
+
+const (
+	STORAGE_CSVT    = "csvt"
+	STORAGE_JOURNAL = "journal"
+	STORAGE_BOLT    = "bolt"
+)
+
+const defaultJournalCompact = 1000
+
+// Storage selects how the web data is persisted. The CSVT file is rewritten
+// on every change, while the journal appends the changes and only rewrites
+// the CSVT file once it holds the given number of records. Both keep the data
+// in memory, unlike the embedded database. The audit trail is always
+// journaled, with the same compaction.
+type Storage struct {
+	WebData        string
+	JournalCompact int
+}
+
+func storageArgs(kargs map[string]utils.Argument) Storage {
+	webData := strings.ToLower(kargs["GAR_STORAGE_WEB_DATA"].String())
+	switch webData {
+	case STORAGE_CSVT, STORAGE_JOURNAL, STORAGE_BOLT:
+	case "":
+		webData = STORAGE_CSVT
+	default:
+		log.Warningf("Unknown web data storage %q; using %s", webData, STORAGE_CSVT)
+		webData = STORAGE_CSVT
+	}
+
+	compact := kargs["GAR_STORAGE_JOURNAL_COMPACT"].Intd(defaultJournalCompact)
+	if compact < 1 {
+		compact = defaultJournalCompact
+	}
+
+	return Storage{
+		WebData:        webData,
+		JournalCompact: compact,
+	}
+}
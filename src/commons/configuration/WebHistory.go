@@ -0,0 +1,39 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/Rafael24595/go-api-core/src/commons/utils"
+)
+
+const defaultWebHistoryLimit = 10
+const defaultWebHistoryAge = 30
+
+// WebHistory limits the revisions of the web data kept per user, by count and
+// by age. A limit of 0 disables the history and an age of 0 keeps the
+// revisions until the limit is reached.
+type WebHistory struct {
+	Limit  int
+	MaxAge time.Duration
+}
+
+func webHistoryArgs(kargs map[string]utils.Argument) WebHistory {
+	limit := kargs["GAR_WEB_DATA_HISTORY"].Intd(defaultWebHistoryLimit)
+	if limit < 0 {
+		limit = 0
+	}
+
+	age := kargs["GAR_WEB_DATA_HISTORY_AGE"].Intd(defaultWebHistoryAge)
+	if age < 0 {
+		age = 0
+	}
+
+	return WebHistory{
+		Limit:  limit,
+		MaxAge: time.Duration(age) * 24 * time.Hour,
+	}
+}
+
+func (h WebHistory) Enabled() bool {
+	return h.Limit > 0
+}
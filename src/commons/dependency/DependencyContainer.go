@@ -1,6 +1,7 @@
 package dependency
 
 import (
+	"io"
 	"log"
 	"sync"
 
@@ -8,11 +9,30 @@ import (
 	core_dependency "github.com/Rafael24595/go-api-core/src/commons/dependency"
 	core_topic_snapshot "github.com/Rafael24595/go-api-core/src/commons/system/topic/snapshot"
 	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/application/command"
 	"github.com/Rafael24595/go-api-render/src/application/manager"
 	"github.com/Rafael24595/go-api-render/src/commons/configuration"
 	topic_snapshot "github.com/Rafael24595/go-api-render/src/commons/system/topic/snapshot"
+	domain_account "github.com/Rafael24595/go-api-render/src/domain/account"
+	domain_audit "github.com/Rafael24595/go-api-render/src/domain/audit"
+	domain_device "github.com/Rafael24595/go-api-render/src/domain/device"
+	domain_factor "github.com/Rafael24595/go-api-render/src/domain/factor"
+	domain_namespace "github.com/Rafael24595/go-api-render/src/domain/namespace"
+	domain_revision "github.com/Rafael24595/go-api-render/src/domain/revision"
+	domain_role "github.com/Rafael24595/go-api-render/src/domain/role"
+	domain_scope "github.com/Rafael24595/go-api-render/src/domain/scope"
+	domain_setting "github.com/Rafael24595/go-api-render/src/domain/setting"
 	domain_web "github.com/Rafael24595/go-api-render/src/domain/web"
 	"github.com/Rafael24595/go-api-render/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository/account"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository/audit"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository/device"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository/factor"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository/namespace"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository/revision"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository/role"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository/scope"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository/setting"
 	"github.com/Rafael24595/go-api-render/src/infrastructure/repository/web"
 	"github.com/Rafael24595/go-collections/collection"
 )
@@ -24,18 +44,78 @@ var (
 
 type DependencyContainer struct {
 	core_dependency.DependencyContainer
-	ManagerWeb *manager.ManagerWeb
+	ManagerWeb       *manager.ManagerWeb
+	ManagerDevice    *manager.ManagerDevice
+	ManagerSetting   *manager.ManagerSetting
+	ManagerFactor    *manager.ManagerFactor
+	ManagerLockout   *manager.ManagerLockout
+	ManagerAccount   *manager.ManagerAccount
+	ManagerRole      *manager.ManagerRole
+	ManagerScope     *manager.ManagerScope
+	ManagerAudit     *manager.ManagerAudit
+	ManagerPassword  *manager.ManagerPassword
+	ManagerNamespace *manager.ManagerNamespace
+	ManagerRevision  *manager.ManagerRevision
+	ManagerTakeout   *manager.ManagerTakeout
+	ManagerDeletion  *manager.ManagerDeletion
+	repositories     []io.Closer
 }
 
 func Initialize(config configuration.Configuration, dependency core_dependency.DependencyContainer) *DependencyContainer {
 	once.Do(func() {
 		repositoryWeb := loadRepositoryWeb(config)
 
-		managerWeb := loadManagerWeb(repositoryWeb)
+		repositoryDevice := loadRepositoryDevice(config)
+		repositorySetting := loadRepositorySetting(config)
+		repositoryFactor := loadRepositoryFactor(config)
+		repositoryAccount := loadRepositoryAccount(config)
+		repositoryRole := loadRepositoryRole(config)
+		repositoryScope := loadRepositoryScope(config)
+		repositoryAudit := loadRepositoryAudit(config)
+		repositoryNamespace := loadRepositoryNamespace(config)
+		repositoryRevision := loadRepositoryRevision(config)
+
+		managerNamespace := loadManagerNamespace(config, repositoryNamespace)
+		managerRevision := loadManagerRevision(config, repositoryRevision)
+		managerWeb := loadManagerWeb(repositoryWeb, managerNamespace, managerRevision)
+		managerDevice := loadManagerDevice(config, repositoryDevice)
+		managerSetting := loadManagerSetting(repositorySetting)
+		managerFactor := loadManagerFactor(repositoryFactor, managerSetting)
+		managerLockout := loadManagerLockout(config)
+		managerAccount := loadManagerAccount(repositoryAccount)
+		managerRole := loadManagerRole(repositoryRole)
+		managerScope := loadManagerScope(repositoryScope)
+		managerAudit := loadManagerAudit(repositoryAudit)
+		managerPassword := loadManagerPassword(config, managerAccount)
+		managerTakeout := loadManagerTakeout(dependency, managerWeb, managerScope)
+		managerDeletion := loadManagerDeletion(config, dependency, managerWeb, managerDevice, managerFactor, managerLockout, managerAccount, managerRole, managerScope, managerAudit)
 
 		container := &DependencyContainer{
 			DependencyContainer: dependency,
 			ManagerWeb:          managerWeb,
+			ManagerDevice:       managerDevice,
+			ManagerSetting:      managerSetting,
+			ManagerFactor:       managerFactor,
+			ManagerLockout:      managerLockout,
+			ManagerAccount:      managerAccount,
+			ManagerRole:         managerRole,
+			ManagerScope:        managerScope,
+			ManagerAudit:        managerAudit,
+			ManagerPassword:     managerPassword,
+			ManagerNamespace:    managerNamespace,
+			ManagerRevision:     managerRevision,
+			ManagerTakeout:      managerTakeout,
+			ManagerDeletion:     managerDeletion,
+			repositories: []io.Closer{
+				repositoryDevice,
+				repositorySetting,
+				repositoryFactor,
+				repositoryAccount,
+				repositoryRole,
+				repositoryScope,
+				repositoryNamespace,
+				repositoryRevision,
+			},
 		}
 
 		instance = container
@@ -43,16 +123,41 @@ func Initialize(config configuration.Configuration, dependency core_dependency.D
 	return instance
 }
 
+// Close writes the pending changes of the repositories that buffer them, so
+// nothing is lost when the process exits.
+func (c *DependencyContainer) Close() {
+	if err := c.ManagerWeb.Close(); err != nil {
+		log.Printf("The web data cannot be written: %s", err.Error())
+	}
+	if err := c.ManagerAudit.Close(); err != nil {
+		log.Printf("The audit trail cannot be closed: %s", err.Error())
+	}
+	for _, v := range c.repositories {
+		if err := v.Close(); err != nil {
+			log.Printf("A repository cannot be written: %s", err.Error())
+		}
+	}
+}
+
 func loadRepositoryWeb(config configuration.Configuration) domain_web.Repository {
 	var file core_repository.IFileManager[domain_web.WebData]
 	file = core_repository.NewManagerCsvtFile[domain_web.WebData](repository.CSVT_FILE_PATH_WEB_DATA)
 
+	storage := config.Storage()
+	if storage.WebData == configuration.STORAGE_BOLT {
+		return loadRepositoryWebBolt(file, storage)
+	}
+
 	snapshot := config.Snapshot()
 	if snapshot.Enable {
 		topic := topic_snapshot.TOPIC_WEB_DATA
 		file = loadManagerSnapshotFile(topic, snapshot, file)
 	}
 
+	if storage.WebData == configuration.STORAGE_JOURNAL {
+		file = repository.NewManagerJournalFile(repository.JOURNAL_FILE_PATH_WEB_DATA, repository.CSVT_FILE_PATH_WEB_DATA, file, storage.JournalCompact)
+	}
+
 	impl := collection.DictionarySyncEmpty[string, domain_web.WebData]()
 	repository, err := web.InitializeRepositoryMemory(impl, file)
 	if err != nil {
@@ -62,6 +167,193 @@ func loadRepositoryWeb(config configuration.Configuration) domain_web.Repository
 	return repository
 }
 
+// loadRepositoryWebBolt opens the embedded database and registers the
+// command that imports the file storage, journal included, into it.
+func loadRepositoryWebBolt(file core_repository.IFileManager[domain_web.WebData], storage configuration.Storage) domain_web.Repository {
+	repositoryBolt, err := web.InitializeRepositoryBolt(repository.BOLT_FILE_PATH_WEB_DATA)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	source := repository.NewManagerJournalFile(repository.JOURNAL_FILE_PATH_WEB_DATA, repository.CSVT_FILE_PATH_WEB_DATA, file, storage.JournalCompact)
+	command.RegisterWebDataMigration(source, repositoryBolt)
+
+	return repositoryBolt
+}
+
+func loadRepositoryDevice(config configuration.Configuration) domain_device.Repository {
+	var file core_repository.IFileManager[domain_device.Device]
+	file = core_repository.NewManagerCsvtFile[domain_device.Device](repository.CSVT_FILE_PATH_DEVICE)
+
+	snapshot := config.Snapshot()
+	if snapshot.Enable {
+		topic := topic_snapshot.TOPIC_DEVICE
+		file = loadManagerSnapshotFile(topic, snapshot, file)
+	}
+
+	impl := collection.DictionarySyncEmpty[string, domain_device.Device]()
+	repository, err := device.InitializeRepositoryMemory(impl, file)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return repository
+}
+
+func loadRepositorySetting(config configuration.Configuration) domain_setting.Repository {
+	var file core_repository.IFileManager[domain_setting.Setting]
+	file = core_repository.NewManagerCsvtFile[domain_setting.Setting](repository.CSVT_FILE_PATH_SETTING)
+
+	snapshot := config.Snapshot()
+	if snapshot.Enable {
+		topic := topic_snapshot.TOPIC_SETTING
+		file = loadManagerSnapshotFile(topic, snapshot, file)
+	}
+
+	impl := collection.DictionarySyncEmpty[string, domain_setting.Setting]()
+	repository, err := setting.InitializeRepositoryMemory(impl, file)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return repository
+}
+
+func loadRepositoryFactor(config configuration.Configuration) domain_factor.Repository {
+	var file core_repository.IFileManager[domain_factor.Factor]
+	file = core_repository.NewManagerCsvtFile[domain_factor.Factor](repository.CSVT_FILE_PATH_FACTOR)
+
+	snapshot := config.Snapshot()
+	if snapshot.Enable {
+		topic := topic_snapshot.TOPIC_FACTOR
+		file = loadManagerSnapshotFile(topic, snapshot, file)
+	}
+
+	impl := collection.DictionarySyncEmpty[string, domain_factor.Factor]()
+	repository, err := factor.InitializeRepositoryMemory(impl, file)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return repository
+}
+
+func loadRepositoryAccount(config configuration.Configuration) domain_account.Repository {
+	var file core_repository.IFileManager[domain_account.Account]
+	file = core_repository.NewManagerCsvtFile[domain_account.Account](repository.CSVT_FILE_PATH_ACCOUNT)
+
+	snapshot := config.Snapshot()
+	if snapshot.Enable {
+		topic := topic_snapshot.TOPIC_ACCOUNT
+		file = loadManagerSnapshotFile(topic, snapshot, file)
+	}
+
+	impl := collection.DictionarySyncEmpty[string, domain_account.Account]()
+	repository, err := account.InitializeRepositoryMemory(impl, file)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return repository
+}
+
+func loadRepositoryRole(config configuration.Configuration) domain_role.Repository {
+	var file core_repository.IFileManager[domain_role.Role]
+	file = core_repository.NewManagerCsvtFile[domain_role.Role](repository.CSVT_FILE_PATH_ROLE)
+
+	snapshot := config.Snapshot()
+	if snapshot.Enable {
+		topic := topic_snapshot.TOPIC_ROLE
+		file = loadManagerSnapshotFile(topic, snapshot, file)
+	}
+
+	impl := collection.DictionarySyncEmpty[string, domain_role.Role]()
+	repository, err := role.InitializeRepositoryMemory(impl, file)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return repository
+}
+
+func loadRepositoryScope(config configuration.Configuration) domain_scope.Repository {
+	var file core_repository.IFileManager[domain_scope.Policy]
+	file = core_repository.NewManagerCsvtFile[domain_scope.Policy](repository.CSVT_FILE_PATH_SCOPE)
+
+	snapshot := config.Snapshot()
+	if snapshot.Enable {
+		topic := topic_snapshot.TOPIC_SCOPE
+		file = loadManagerSnapshotFile(topic, snapshot, file)
+	}
+
+	impl := collection.DictionarySyncEmpty[string, domain_scope.Policy]()
+	repository, err := scope.InitializeRepositoryMemory(impl, file)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return repository
+}
+
+func loadRepositoryAudit(config configuration.Configuration) domain_audit.Repository {
+	var file core_repository.IFileManager[domain_audit.Event]
+	file = core_repository.NewManagerCsvtFile[domain_audit.Event](repository.CSVT_FILE_PATH_AUDIT)
+
+	snapshot := config.Snapshot()
+	if snapshot.Enable {
+		topic := topic_snapshot.TOPIC_AUDIT
+		file = loadManagerSnapshotFile(topic, snapshot, file)
+	}
+
+	journal := repository.NewManagerJournalFile(repository.JOURNAL_FILE_PATH_AUDIT, repository.CSVT_FILE_PATH_AUDIT, file, config.Storage().JournalCompact)
+
+	impl := collection.DictionarySyncEmpty[string, domain_audit.Event]()
+	repository, err := audit.InitializeRepositoryMemory(impl, journal, config.Audit())
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return repository
+}
+
+func loadRepositoryNamespace(config configuration.Configuration) domain_namespace.Repository {
+	var file core_repository.IFileManager[domain_namespace.Namespace]
+	file = core_repository.NewManagerCsvtFile[domain_namespace.Namespace](repository.CSVT_FILE_PATH_NAMESPACE)
+
+	snapshot := config.Snapshot()
+	if snapshot.Enable {
+		topic := topic_snapshot.TOPIC_NAMESPACE
+		file = loadManagerSnapshotFile(topic, snapshot, file)
+	}
+
+	impl := collection.DictionarySyncEmpty[string, domain_namespace.Namespace]()
+	repository, err := namespace.InitializeRepositoryMemory(impl, file)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return repository
+}
+
+func loadRepositoryRevision(config configuration.Configuration) domain_revision.Repository {
+	var file core_repository.IFileManager[domain_revision.Revision]
+	file = core_repository.NewManagerCsvtFile[domain_revision.Revision](repository.CSVT_FILE_PATH_REVISION)
+
+	snapshot := config.Snapshot()
+	if snapshot.Enable {
+		topic := topic_snapshot.TOPIC_WEB_REVISION
+		file = loadManagerSnapshotFile(topic, snapshot, file)
+	}
+
+	impl := collection.DictionarySyncEmpty[string, domain_revision.Revision]()
+	repository, err := revision.InitializeRepositoryMemory(impl, file)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return repository
+}
+
 func loadManagerSnapshotFile[T core_repository.IStructure](topic core_topic_snapshot.TopicSnapshot, snapshot core_configuration.Snapshot, file core_repository.IFileManager[T]) core_repository.IFileManager[T] {
 	return core_repository.
 		BuilderManagerSnapshotFile(topic, file).
@@ -70,6 +362,99 @@ func loadManagerSnapshotFile[T core_repository.IStructure](topic core_topic_snap
 		Make()
 }
 
-func loadManagerWeb(web domain_web.Repository) *manager.ManagerWeb {
-	return manager.NewManagerWeb(web)
+func loadManagerWeb(web domain_web.Repository, namespace *manager.ManagerNamespace, revision *manager.ManagerRevision) *manager.ManagerWeb {
+	return manager.NewManagerWeb(web, namespace, revision)
+}
+
+func loadManagerDevice(config configuration.Configuration, device domain_device.Repository) *manager.ManagerDevice {
+	return manager.NewManagerDevice(device, config.Session())
+}
+
+func loadManagerSetting(setting domain_setting.Repository) *manager.ManagerSetting {
+	return manager.NewManagerSetting(setting)
+}
+
+func loadManagerFactor(factor domain_factor.Repository, setting *manager.ManagerSetting) *manager.ManagerFactor {
+	return manager.NewManagerFactor(factor, setting)
+}
+
+func loadManagerLockout(config configuration.Configuration) *manager.ManagerLockout {
+	managerLockout := manager.NewManagerLockout(config.Lockout())
+	command.RegisterLockout(managerLockout)
+	return managerLockout
+}
+
+func loadManagerAccount(account domain_account.Repository) *manager.ManagerAccount {
+	return manager.NewManagerAccount(account)
+}
+
+func loadManagerRole(role domain_role.Repository) *manager.ManagerRole {
+	return manager.NewManagerRole(role)
+}
+
+func loadManagerScope(scope domain_scope.Repository) *manager.ManagerScope {
+	return manager.NewManagerScope(scope)
+}
+
+func loadManagerAudit(audit domain_audit.Repository) *manager.ManagerAudit {
+	return manager.NewManagerAudit(audit)
+}
+
+func loadManagerPassword(config configuration.Configuration, account *manager.ManagerAccount) *manager.ManagerPassword {
+	return manager.NewManagerPassword(config.Password(), account)
+}
+
+func loadManagerNamespace(config configuration.Configuration, namespace domain_namespace.Repository) *manager.ManagerNamespace {
+	return manager.NewManagerNamespace(namespace, config.WebDataLimit, config.WebDataOwnerLimit)
+}
+
+func loadManagerRevision(config configuration.Configuration, revision domain_revision.Repository) *manager.ManagerRevision {
+	return manager.NewManagerRevision(revision, config.WebHistory())
+}
+
+func loadManagerTakeout(dependency core_dependency.DependencyContainer, web *manager.ManagerWeb, scope *manager.ManagerScope) *manager.ManagerTakeout {
+	return manager.NewManagerTakeout(
+		dependency.ManagerRequest,
+		dependency.ManagerContext,
+		dependency.ManagerCollection,
+		dependency.ManagerGroup,
+		dependency.ManagerEndPoint,
+		dependency.ManagerMetrics,
+		dependency.ManagerToken,
+		dependency.ManagerSessionData,
+		web,
+		scope,
+	)
+}
+
+func loadManagerDeletion(
+	config configuration.Configuration,
+	dependency core_dependency.DependencyContainer,
+	web *manager.ManagerWeb,
+	device *manager.ManagerDevice,
+	factor *manager.ManagerFactor,
+	lockout *manager.ManagerLockout,
+	account *manager.ManagerAccount,
+	role *manager.ManagerRole,
+	scope *manager.ManagerScope,
+	audit *manager.ManagerAudit,
+) *manager.ManagerDeletion {
+	return manager.NewManagerDeletion(
+		config.Deletion(),
+		dependency.ManagerCollection,
+		dependency.ManagerGroup,
+		dependency.ManagerHistoric,
+		dependency.ManagerEndPoint,
+		dependency.ManagerMetrics,
+		dependency.ManagerToken,
+		dependency.ManagerSessionData,
+		web,
+		device,
+		factor,
+		lockout,
+		account,
+		role,
+		scope,
+		audit,
+	)
 }
@@ -0,0 +1,144 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Rafael24595/go-log/log"
+)
+
+const OIDC_CATEGORY = "OIDC"
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwksDocument struct {
+	Keys []jwk `json:"keys"`
+}
+
+type keySet struct {
+	keys map[string]crypto.PublicKey
+}
+
+func parseKeySet(document jwksDocument) *keySet {
+	keys := make(map[string]crypto.PublicKey)
+	for _, v := range document.Keys {
+		if v.Use != "" && v.Use != "sig" {
+			continue
+		}
+
+		key, err := v.public()
+		if err != nil {
+			log.Customf(OIDC_CATEGORY, "Provider key %q ignored: %s", v.Kid, err.Error())
+			continue
+		}
+
+		keys[v.Kid] = key
+	}
+
+	return &keySet{
+		keys: keys,
+	}
+}
+
+func (s *keySet) find(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, v := range s.keys {
+			return v, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (k jwk) public() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: n,
+			E: int(e.Int64()),
+		}, nil
+	case "EC":
+		curve, err := ellipticCurve(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("the point is not on the curve")
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     x,
+			Y:     y,
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func ellipticCurve(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", name)
+	}
+}
+
+func decodeInt(segment string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty key component")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
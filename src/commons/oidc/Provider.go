@@ -0,0 +1,347 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const pendingLifetime = 10 * time.Minute
+const requestTimeout = 10 * time.Second
+
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+type Identity struct {
+	Issuer   string
+	Subject  string
+	Username string
+	Groups   []string
+	Claims   jwt.MapClaims
+}
+
+type pending struct {
+	nonce    string
+	verifier string
+	expires  time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IdToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+type Provider struct {
+	mu        sync.Mutex
+	config    configuration.Oidc
+	client    *http.Client
+	discovery *Discovery
+	keys      *keySet
+	pending   map[string]pending
+}
+
+func NewProvider(config configuration.Oidc) *Provider {
+	return &Provider{
+		config: config,
+		client: &http.Client{
+			Timeout: requestTimeout,
+		},
+		pending: make(map[string]pending),
+	}
+}
+
+func (p *Provider) Config() configuration.Oidc {
+	return p.config
+}
+
+// Authorize starts an authorization-code flow protected by PKCE and returns
+// the state that binds the browser to the flow and the URL to redirect it to.
+func (p *Provider) Authorize() (string, string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", "", err
+	}
+
+	state := randomString()
+	nonce := randomString()
+	verifier := randomString()
+
+	p.mu.Lock()
+	p.purge()
+	p.pending[state] = pending{
+		nonce:    nonce,
+		verifier: verifier,
+		expires:  time.Now().Add(pendingLifetime),
+	}
+	p.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(verifier))
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientId)
+	query.Set("redirect_uri", p.config.Redirect)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return state, discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems the authorization code and returns the identity described
+// by the validated ID token.
+func (p *Provider) Exchange(state, code string) (*Identity, error) {
+	p.mu.Lock()
+	flow, ok := p.pending[state]
+	delete(p.pending, state)
+	p.mu.Unlock()
+
+	if !ok || time.Now().After(flow.expires) {
+		return nil, errors.New("the authorization state is unknown or has expired")
+	}
+
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.Redirect)
+	form.Set("client_id", p.config.ClientId)
+	form.Set("code_verifier", flow.verifier)
+
+	request, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(p.config.ClientId), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	response := tokenResponse{}
+	if err := p.fetch(request, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Error != "" {
+		return nil, fmt.Errorf("the token endpoint rejected the code: %s %s", response.Error, response.Description)
+	}
+
+	if response.IdToken == "" {
+		return nil, errors.New("the token response does not include an ID token")
+	}
+
+	return p.validate(discovery, response.IdToken, flow.nonce)
+}
+
+func (p *Provider) validate(discovery *Discovery, raw, nonce string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, p.verification,
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt())
+	if err != nil {
+		return nil, err
+	}
+
+	if claimNonce, _ := claims["nonce"].(string); claimNonce != nonce {
+		return nil, errors.New("the ID token nonce does not match the authorization request")
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, errors.New("the ID token does not define a subject")
+	}
+
+	username, _ := claims[p.config.UsernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("the ID token does not define the username claim %q", p.config.UsernameClaim)
+	}
+
+	return &Identity{
+		Issuer:   discovery.Issuer,
+		Subject:  subject,
+		Username: username,
+		Groups:   claimStrings(claims[p.config.GroupsClaim]),
+		Claims:   claims,
+	}, nil
+}
+
+func (p *Provider) verification(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	keys, err := p.keySet(false)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, ok := keys.find(kid); ok {
+		return key, nil
+	}
+
+	keys, err = p.keySet(true)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, ok := keys.find(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key identifier %q", kid)
+}
+
+func (p *Provider) discover() (*Discovery, error) {
+	p.mu.Lock()
+	cached := p.discovery
+	p.mu.Unlock()
+
+	if cached != nil {
+		return cached, nil
+	}
+
+	request, err := http.NewRequest(http.MethodGet, p.config.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	discovery := &Discovery{}
+	if err := p.fetch(request, discovery); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.config.Issuer {
+		return nil, fmt.Errorf("the discovered issuer %q does not match the configured issuer", discovery.Issuer)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksUri == "" {
+		return nil, errors.New("the discovery document is incomplete")
+	}
+
+	p.mu.Lock()
+	p.discovery = discovery
+	p.mu.Unlock()
+
+	return discovery, nil
+}
+
+func (p *Provider) keySet(refresh bool) (*keySet, error) {
+	p.mu.Lock()
+	cached := p.keys
+	p.mu.Unlock()
+
+	if cached != nil && !refresh {
+		return cached, nil
+	}
+
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(http.MethodGet, discovery.JwksUri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	document := jwksDocument{}
+	if err := p.fetch(request, &document); err != nil {
+		return nil, err
+	}
+
+	keys := parseKeySet(document)
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	return keys, nil
+}
+
+func (p *Provider) fetch(request *http.Request, target any) error {
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("the identity provider responded with status %d", response.StatusCode)
+	}
+
+	return json.Unmarshal(body, target)
+}
+
+func (p *Provider) purge() {
+	now := time.Now()
+	for k, v := range p.pending {
+		if now.After(v.expires) {
+			delete(p.pending, k)
+		}
+	}
+}
+
+func claimStrings(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		result := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return []string{}
+	}
+}
+
+func randomString() string {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer)
+}
@@ -0,0 +1,171 @@
+package oidc
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const standInKid = "stand-in"
+const standInCodeLifetime = time.Minute
+const standInTokenLifetime = 5 * time.Minute
+
+type grant struct {
+	clientId  string
+	redirect  string
+	nonce     string
+	challenge string
+	username  string
+	groups    []string
+	expires   time.Time
+}
+
+// StandIn is a minimal identity provider meant for local development. It
+// implements just enough of the authorization-code flow to exercise the
+// client: every authorization request is approved for the given user.
+type StandIn struct {
+	mu      sync.Mutex
+	config  configuration.Oidc
+	private ed25519.PrivateKey
+	grants  map[string]grant
+}
+
+func NewStandIn(config configuration.Oidc) (*StandIn, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StandIn{
+		config:  config,
+		private: private,
+		grants:  make(map[string]grant),
+	}, nil
+}
+
+func (s *StandIn) Discovery(issuer string) Discovery {
+	return Discovery{
+		Issuer:                issuer,
+		AuthorizationEndpoint: issuer + "/authorize",
+		TokenEndpoint:         issuer + "/token",
+		JwksUri:               issuer + "/jwks",
+	}
+}
+
+func (s *StandIn) Jwks() any {
+	public := s.private.Public().(ed25519.PublicKey)
+	return jwksDocument{
+		Keys: []jwk{
+			{
+				Kty: "OKP",
+				Kid: standInKid,
+				Use: "sig",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			},
+		},
+	}
+}
+
+// Authorize approves the authorization request on behalf of the given user
+// and returns the client URL the browser must be redirected to.
+func (s *StandIn) Authorize(query url.Values, username string, groups []string) (string, error) {
+	if query.Get("response_type") != "code" {
+		return "", errors.New("only the authorization-code flow is supported")
+	}
+
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		return "", errors.New("a S256 code challenge is required")
+	}
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirect.String() == "" {
+		return "", errors.New("the redirect URI is not valid")
+	}
+
+	if username == "" {
+		return "", errors.New("the username is required")
+	}
+
+	code := randomString()
+
+	s.mu.Lock()
+	s.grants[code] = grant{
+		clientId:  query.Get("client_id"),
+		redirect:  redirect.String(),
+		nonce:     query.Get("nonce"),
+		challenge: query.Get("code_challenge"),
+		username:  username,
+		groups:    groups,
+		expires:   time.Now().Add(standInCodeLifetime),
+	}
+	s.mu.Unlock()
+
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirect.RawQuery = params.Encode()
+
+	return redirect.String(), nil
+}
+
+// Token redeems an authorization code and returns a signed ID token.
+func (s *StandIn) Token(issuer, clientId string, form url.Values) (any, error) {
+	code := form.Get("code")
+
+	s.mu.Lock()
+	grant, ok := s.grants[code]
+	delete(s.grants, code)
+	s.mu.Unlock()
+
+	if !ok || time.Now().After(grant.expires) {
+		return nil, errors.New("the authorization code is unknown or has expired")
+	}
+
+	if clientId == "" {
+		clientId = form.Get("client_id")
+	}
+
+	if grant.clientId != clientId || grant.redirect != form.Get("redirect_uri") {
+		return nil, errors.New("the code was issued to another client")
+	}
+
+	verifier := sha256.Sum256([]byte(form.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(verifier[:]) != grant.challenge {
+		return nil, errors.New("the code verifier does not match the challenge")
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                  issuer,
+		"aud":                  grant.clientId,
+		"sub":                  grant.username,
+		"iat":                  now.Unix(),
+		"exp":                  now.Add(standInTokenLifetime).Unix(),
+		"nonce":                grant.nonce,
+		s.config.UsernameClaim: grant.username,
+		s.config.GroupsClaim:   grant.groups,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = standInKid
+
+	signed, err := token.SignedString(s.private)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenResponse{
+		AccessToken: randomString(),
+		IdToken:     signed,
+		TokenType:   "Bearer",
+	}, nil
+}
@@ -0,0 +1,522 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a compiled JSON Schema. Only the validation keywords that do not
+// need to resolve other documents are supported; annotations are ignored and
+// any other keyword is rejected, so a schema never looks stricter than it is.
+type Schema struct {
+	always        *bool
+	types         []string
+	enum          []any
+	hasConst      bool
+	constant      any
+	properties    map[string]*Schema
+	required      []string
+	additional    *Schema
+	minProperties *int
+	maxProperties *int
+	items         *Schema
+	minItems      *int
+	maxItems      *int
+	uniqueItems   bool
+	minLength     *int
+	maxLength     *int
+	pattern       *regexp.Regexp
+	minimum       *float64
+	maximum       *float64
+	exclusiveMin  *float64
+	exclusiveMax  *float64
+	multipleOf    *float64
+	allOf         []*Schema
+	anyOf         []*Schema
+	oneOf         []*Schema
+	not           *Schema
+}
+
+type Error struct {
+	Path    string
+	Message string
+}
+
+var types = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+var keywords = []string{
+	"type", "enum", "const",
+	"properties", "required", "additionalProperties", "minProperties", "maxProperties",
+	"items", "minItems", "maxItems", "uniqueItems",
+	"minLength", "maxLength", "pattern",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf",
+	"allOf", "anyOf", "oneOf", "not",
+}
+
+var annotations = []string{"$schema", "$id", "$comment", "title", "description", "default", "examples", "deprecated", "readOnly", "writeOnly"}
+
+func Compile(raw []byte) (*Schema, error) {
+	var document any
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, fmt.Errorf("the schema is not valid JSON: %s", err.Error())
+	}
+	return compile(document, "")
+}
+
+func compile(document any, path string) (*Schema, error) {
+	if value, ok := document.(bool); ok {
+		return &Schema{always: &value}, nil
+	}
+
+	object, ok := document.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("the schema at %q must be an object or a boolean", pointer(path))
+	}
+
+	names := make([]string, 0, len(object))
+	for k := range object {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, v := range names {
+		if !slices.Contains(keywords, v) && !slices.Contains(annotations, v) {
+			return nil, fmt.Errorf("the keyword %q at %q is not supported", v, pointer(path))
+		}
+	}
+
+	result := &Schema{}
+
+	var err error
+	if result.types, err = compileTypes(object["type"], path); err != nil {
+		return nil, err
+	}
+
+	if value, ok := object["enum"]; ok {
+		enum, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("the enum at %q must be an array", pointer(path))
+		}
+		result.enum = enum
+	}
+
+	if value, ok := object["const"]; ok {
+		result.hasConst = true
+		result.constant = value
+	}
+
+	if value, ok := object["properties"]; ok {
+		properties, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("the properties at %q must be an object", pointer(path))
+		}
+		result.properties = make(map[string]*Schema, len(properties))
+		for k, v := range properties {
+			if result.properties[k], err = compile(v, path+"/properties/"+k); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if value, ok := object["required"]; ok {
+		required, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("the required list at %q must be an array", pointer(path))
+		}
+		for _, v := range required {
+			name, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("the required list at %q must only contain strings", pointer(path))
+			}
+			result.required = append(result.required, name)
+		}
+	}
+
+	if value, ok := object["additionalProperties"]; ok {
+		if result.additional, err = compile(value, path+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+
+	if value, ok := object["items"]; ok {
+		if result.items, err = compile(value, path+"/items"); err != nil {
+			return nil, err
+		}
+	}
+
+	if value, ok := object["not"]; ok {
+		if result.not, err = compile(value, path+"/not"); err != nil {
+			return nil, err
+		}
+	}
+
+	compositions := []struct {
+		keyword string
+		target  *[]*Schema
+	}{
+		{"allOf", &result.allOf},
+		{"anyOf", &result.anyOf},
+		{"oneOf", &result.oneOf},
+	}
+
+	for _, c := range compositions {
+		value, ok := object[c.keyword]
+		if !ok {
+			continue
+		}
+		list, ok := value.([]any)
+		if !ok || len(list) == 0 {
+			return nil, fmt.Errorf("the %s at %q must be a non-empty array", c.keyword, pointer(path))
+		}
+		for i, v := range list {
+			compiled, err := compile(v, fmt.Sprintf("%s/%s/%d", path, c.keyword, i))
+			if err != nil {
+				return nil, err
+			}
+			*c.target = append(*c.target, compiled)
+		}
+	}
+
+	counters := []struct {
+		keyword string
+		target  **int
+	}{
+		{"minProperties", &result.minProperties},
+		{"maxProperties", &result.maxProperties},
+		{"minItems", &result.minItems},
+		{"maxItems", &result.maxItems},
+		{"minLength", &result.minLength},
+		{"maxLength", &result.maxLength},
+	}
+
+	for _, c := range counters {
+		value, ok := object[c.keyword]
+		if !ok {
+			continue
+		}
+		number, ok := value.(float64)
+		if !ok || number < 0 || number != math.Trunc(number) {
+			return nil, fmt.Errorf("the %s at %q must be a non-negative integer", c.keyword, pointer(path))
+		}
+		count := int(number)
+		*c.target = &count
+	}
+
+	bounds := []struct {
+		keyword string
+		target  **float64
+	}{
+		{"minimum", &result.minimum},
+		{"maximum", &result.maximum},
+		{"exclusiveMinimum", &result.exclusiveMin},
+		{"exclusiveMaximum", &result.exclusiveMax},
+	}
+
+	for _, b := range bounds {
+		value, ok := object[b.keyword]
+		if !ok {
+			continue
+		}
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("the %s at %q must be a number", b.keyword, pointer(path))
+		}
+		*b.target = &number
+	}
+
+	if value, ok := object["multipleOf"]; ok {
+		number, ok := value.(float64)
+		if !ok || number <= 0 {
+			return nil, fmt.Errorf("the multipleOf at %q must be a number greater than 0", pointer(path))
+		}
+		result.multipleOf = &number
+	}
+
+	if value, ok := object["uniqueItems"]; ok {
+		unique, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("the uniqueItems at %q must be a boolean", pointer(path))
+		}
+		result.uniqueItems = unique
+	}
+
+	if value, ok := object["pattern"]; ok {
+		expression, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("the pattern at %q must be a string", pointer(path))
+		}
+		if result.pattern, err = regexp.Compile(expression); err != nil {
+			return nil, fmt.Errorf("the pattern at %q is not valid: %s", pointer(path), err.Error())
+		}
+	}
+
+	return result, nil
+}
+
+func compileTypes(value any, path string) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	names := make([]string, 0)
+	switch v := value.(type) {
+	case string:
+		names = append(names, v)
+	case []any:
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("the type at %q must be a string or an array of strings", pointer(path))
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, fmt.Errorf("the type at %q must be a string or an array of strings", pointer(path))
+	}
+
+	for _, v := range names {
+		if !slices.Contains(types, v) {
+			return nil, fmt.Errorf("the type %q at %q does not exist", v, pointer(path))
+		}
+	}
+
+	return names, nil
+}
+
+// Validate returns the errors of the decoded JSON value against the schema,
+// sorted by their location.
+func (s *Schema) Validate(value any) []Error {
+	errors := s.validate(value, "")
+	sort.SliceStable(errors, func(i, j int) bool {
+		return errors[i].Path < errors[j].Path
+	})
+	return errors
+}
+
+func (s *Schema) validate(value any, path string) []Error {
+	if s.always != nil {
+		if *s.always {
+			return nil
+		}
+		return []Error{fail(path, "no value is allowed")}
+	}
+
+	errors := make([]Error, 0)
+
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(t string) bool { return isType(value, t) }) {
+		return append(errors, fail(path, fmt.Sprintf("the value must be of type %s", strings.Join(s.types, " or "))))
+	}
+
+	if s.enum != nil && !slices.ContainsFunc(s.enum, func(v any) bool { return reflect.DeepEqual(v, value) }) {
+		errors = append(errors, fail(path, "the value is not one of the allowed ones"))
+	}
+
+	if s.hasConst && !reflect.DeepEqual(s.constant, value) {
+		errors = append(errors, fail(path, "the value is not the allowed one"))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		errors = append(errors, s.validateObject(v, path)...)
+	case []any:
+		errors = append(errors, s.validateArray(v, path)...)
+	case string:
+		errors = append(errors, s.validateString(v, path)...)
+	case float64:
+		errors = append(errors, s.validateNumber(v, path)...)
+	}
+
+	for _, v := range s.allOf {
+		errors = append(errors, v.validate(value, path)...)
+	}
+
+	if len(s.anyOf) > 0 && s.matches(s.anyOf, value) == 0 {
+		errors = append(errors, fail(path, "the value does not match any of the allowed schemas"))
+	}
+
+	if len(s.oneOf) > 0 && s.matches(s.oneOf, value) != 1 {
+		errors = append(errors, fail(path, "the value must match exactly one of the allowed schemas"))
+	}
+
+	if s.not != nil && len(s.not.validate(value, path)) == 0 {
+		errors = append(errors, fail(path, "the value matches a forbidden schema"))
+	}
+
+	return errors
+}
+
+func (s *Schema) validateObject(value map[string]any, path string) []Error {
+	errors := make([]Error, 0)
+
+	for _, v := range s.required {
+		if _, ok := value[v]; !ok {
+			errors = append(errors, fail(path+"/"+v, "the property is required"))
+		}
+	}
+
+	for k, v := range value {
+		if property, ok := s.properties[k]; ok {
+			errors = append(errors, property.validate(v, path+"/"+k)...)
+			continue
+		}
+		if s.additional != nil {
+			if s.additional.always != nil && !*s.additional.always {
+				errors = append(errors, fail(path+"/"+k, "the property is not allowed"))
+				continue
+			}
+			errors = append(errors, s.additional.validate(v, path+"/"+k)...)
+		}
+	}
+
+	if s.minProperties != nil && len(value) < *s.minProperties {
+		errors = append(errors, fail(path, fmt.Sprintf("the object must have at least %d properties", *s.minProperties)))
+	}
+
+	if s.maxProperties != nil && len(value) > *s.maxProperties {
+		errors = append(errors, fail(path, fmt.Sprintf("the object must have at most %d properties", *s.maxProperties)))
+	}
+
+	return errors
+}
+
+func (s *Schema) validateArray(value []any, path string) []Error {
+	errors := make([]Error, 0)
+
+	if s.items != nil {
+		for i, v := range value {
+			errors = append(errors, s.items.validate(v, path+"/"+strconv.Itoa(i))...)
+		}
+	}
+
+	if s.minItems != nil && len(value) < *s.minItems {
+		errors = append(errors, fail(path, fmt.Sprintf("the array must have at least %d items", *s.minItems)))
+	}
+
+	if s.maxItems != nil && len(value) > *s.maxItems {
+		errors = append(errors, fail(path, fmt.Sprintf("the array must have at most %d items", *s.maxItems)))
+	}
+
+	if s.uniqueItems && !unique(value) {
+		errors = append(errors, fail(path, "the array items must be unique"))
+	}
+
+	return errors
+}
+
+func (s *Schema) validateString(value, path string) []Error {
+	errors := make([]Error, 0)
+	length := utf8.RuneCountInString(value)
+
+	if s.minLength != nil && length < *s.minLength {
+		errors = append(errors, fail(path, fmt.Sprintf("the string must have at least %d characters", *s.minLength)))
+	}
+
+	if s.maxLength != nil && length > *s.maxLength {
+		errors = append(errors, fail(path, fmt.Sprintf("the string must have at most %d characters", *s.maxLength)))
+	}
+
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		errors = append(errors, fail(path, fmt.Sprintf("the string must match %q", s.pattern.String())))
+	}
+
+	return errors
+}
+
+func (s *Schema) validateNumber(value float64, path string) []Error {
+	errors := make([]Error, 0)
+
+	if s.minimum != nil && value < *s.minimum {
+		errors = append(errors, fail(path, fmt.Sprintf("the number must be at least %v", *s.minimum)))
+	}
+
+	if s.maximum != nil && value > *s.maximum {
+		errors = append(errors, fail(path, fmt.Sprintf("the number must be at most %v", *s.maximum)))
+	}
+
+	if s.exclusiveMin != nil && value <= *s.exclusiveMin {
+		errors = append(errors, fail(path, fmt.Sprintf("the number must be greater than %v", *s.exclusiveMin)))
+	}
+
+	if s.exclusiveMax != nil && value >= *s.exclusiveMax {
+		errors = append(errors, fail(path, fmt.Sprintf("the number must be less than %v", *s.exclusiveMax)))
+	}
+
+	if s.multipleOf != nil {
+		quotient := value / *s.multipleOf
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			errors = append(errors, fail(path, fmt.Sprintf("the number must be a multiple of %v", *s.multipleOf)))
+		}
+	}
+
+	return errors
+}
+
+func (s *Schema) matches(schemas []*Schema, value any) int {
+	count := 0
+	for _, v := range schemas {
+		if len(v.validate(value, "")) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func unique(value []any) bool {
+	for i := range value {
+		for j := i + 1; j < len(value); j++ {
+			if reflect.DeepEqual(value[i], value[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isType(value any, name string) bool {
+	switch name {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	default:
+		return false
+	}
+}
+
+func fail(path, message string) Error {
+	return Error{
+		Path:    pointer(path),
+		Message: message,
+	}
+}
+
+func pointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
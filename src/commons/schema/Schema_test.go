@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	cases := []struct {
+		name   string
+		schema string
+		err    string
+	}{
+		{"boolean", `true`, ""},
+		{"empty", `{}`, ""},
+		{"annotations", `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "t", "description": "d", "default": 1, "examples": [1]}`, ""},
+		{"not a schema", `1`, "must be an object or a boolean"},
+		{"not json", `{`, "not valid JSON"},
+		{"unknown type", `{"type": "date"}`, "does not exist"},
+		{"ref", `{"$ref": "#/$defs/a"}`, `"$ref"`},
+		{"format", `{"type": "string", "format": "email"}`, `"format"`},
+		{"dependent required", `{"dependentRequired": {"a": ["b"]}}`, `"dependentRequired"`},
+		{"property names", `{"propertyNames": {"maxLength": 3}}`, `"propertyNames"`},
+		{"conditional", `{"then": {"type": "string"}}`, `"then"`},
+		{"nested unknown", `{"properties": {"a": {"contains": {}}}}`, `"/properties/a"`},
+		{"negative counter", `{"minItems": -1}`, "non-negative integer"},
+		{"fractional counter", `{"maxLength": 1.5}`, "non-negative integer"},
+		{"zero multiple", `{"multipleOf": 0}`, "greater than 0"},
+		{"unique not boolean", `{"uniqueItems": 1}`, "must be a boolean"},
+		{"empty composition", `{"anyOf": []}`, "non-empty array"},
+		{"invalid pattern", `{"pattern": "("}`, "is not valid"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := Compile([]byte(c.schema))
+			if c.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Fatalf("expected an error containing %q, got %v", c.err, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		schema string
+		value  string
+		errors []string
+	}{
+		{"true", `true`, `1`, nil},
+		{"false", `false`, `1`, []string{"/"}},
+		{"type", `{"type": "string"}`, `"a"`, nil},
+		{"type mismatch", `{"type": "string"}`, `1`, []string{"/"}},
+		{"type list", `{"type": ["string", "null"]}`, `null`, nil},
+		{"integer", `{"type": "integer"}`, `1.0`, nil},
+		{"integer fraction", `{"type": "integer"}`, `1.5`, []string{"/"}},
+		{"enum", `{"enum": ["a", 1]}`, `1`, nil},
+		{"enum mismatch", `{"enum": ["a", 1]}`, `"b"`, []string{"/"}},
+		{"const", `{"const": {"a": 1}}`, `{"a": 1}`, nil},
+		{"const mismatch", `{"const": {"a": 1}}`, `{"a": 2}`, []string{"/"}},
+		{"properties", `{"properties": {"a": {"type": "number"}}}`, `{"a": "x", "b": "y"}`, []string{"/a"}},
+		{"required", `{"required": ["a", "b"]}`, `{"a": 1}`, []string{"/b"}},
+		{"additional false", `{"properties": {"a": {}}, "additionalProperties": false}`, `{"a": 1, "b": 2}`, []string{"/b"}},
+		{"additional schema", `{"additionalProperties": {"type": "string"}}`, `{"a": "x", "b": 2}`, []string{"/b"}},
+		{"min properties", `{"minProperties": 2}`, `{"a": 1}`, []string{"/"}},
+		{"max properties", `{"maxProperties": 1}`, `{"a": 1, "b": 2}`, []string{"/"}},
+		{"items", `{"items": {"type": "string"}}`, `["a", 1]`, []string{"/1"}},
+		{"min items", `{"minItems": 2}`, `[1]`, []string{"/"}},
+		{"max items", `{"maxItems": 1}`, `[1, 2]`, []string{"/"}},
+		{"unique items", `{"uniqueItems": true}`, `[1, {"a": 1}, "1"]`, nil},
+		{"unique items repeated", `{"uniqueItems": true}`, `[1, {"a": 1}, {"a": 1}]`, []string{"/"}},
+		{"unique items disabled", `{"uniqueItems": false}`, `[1, 1]`, nil},
+		{"min length", `{"minLength": 2}`, `"é"`, []string{"/"}},
+		{"max length", `{"maxLength": 2}`, `"éé"`, nil},
+		{"max length exceeded", `{"maxLength": 2}`, `"abc"`, []string{"/"}},
+		{"pattern", `{"pattern": "^[a-z]+$"}`, `"abc1"`, []string{"/"}},
+		{"minimum", `{"minimum": 1}`, `1`, nil},
+		{"minimum below", `{"minimum": 1}`, `0.5`, []string{"/"}},
+		{"maximum", `{"maximum": 1}`, `2`, []string{"/"}},
+		{"exclusive minimum", `{"exclusiveMinimum": 1}`, `1`, []string{"/"}},
+		{"exclusive maximum", `{"exclusiveMaximum": 1}`, `1`, []string{"/"}},
+		{"multiple of", `{"multipleOf": 0.1}`, `0.3`, nil},
+		{"multiple of mismatch", `{"multipleOf": 2}`, `3`, []string{"/"}},
+		{"keywords ignore other types", `{"minLength": 2, "minimum": 5}`, `true`, nil},
+		{"all of", `{"allOf": [{"minimum": 1}, {"maximum": 2}]}`, `3`, []string{"/"}},
+		{"any of", `{"anyOf": [{"type": "string"}, {"type": "null"}]}`, `1`, []string{"/"}},
+		{"one of", `{"oneOf": [{"type": "number"}, {"minimum": 0}]}`, `1`, []string{"/"}},
+		{"one of single", `{"oneOf": [{"type": "number"}, {"type": "string"}]}`, `1`, nil},
+		{"not", `{"not": {"type": "null"}}`, `null`, []string{"/"}},
+		{"sorted paths", `{"properties": {"b": {"type": "string"}, "a": {"type": "string"}}}`, `{"b": 1, "a": 1}`, []string{"/a", "/b"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			compiled, err := Compile([]byte(c.schema))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var value any
+			if err := json.Unmarshal([]byte(c.value), &value); err != nil {
+				t.Fatalf("invalid value: %s", err)
+			}
+
+			errors := compiled.Validate(value)
+			if len(errors) != len(c.errors) {
+				t.Fatalf("expected %d errors, got %v", len(c.errors), errors)
+			}
+			for i, v := range errors {
+				if v.Path != c.errors[i] {
+					t.Fatalf("expected the error %d at %q, got %q", i, c.errors[i], v.Path)
+				}
+			}
+		})
+	}
+}
@@ -5,7 +5,16 @@ import (
 )
 
 const (
-	TOPIC_WEB_DATA core_topic_repository.TopicRepository = "rep_web"
+	TOPIC_WEB_DATA     core_topic_repository.TopicRepository = "rep_web"
+	TOPIC_DEVICE       core_topic_repository.TopicRepository = "rep_device"
+	TOPIC_FACTOR       core_topic_repository.TopicRepository = "rep_factor"
+	TOPIC_SETTING      core_topic_repository.TopicRepository = "rep_setting"
+	TOPIC_ACCOUNT      core_topic_repository.TopicRepository = "rep_account"
+	TOPIC_ROLE         core_topic_repository.TopicRepository = "rep_role"
+	TOPIC_SCOPE        core_topic_repository.TopicRepository = "rep_scope"
+	TOPIC_AUDIT        core_topic_repository.TopicRepository = "rep_audit"
+	TOPIC_NAMESPACE    core_topic_repository.TopicRepository = "rep_namespace"
+	TOPIC_WEB_REVISION core_topic_repository.TopicRepository = "rep_web_revision"
 )
 
 var meta = []core_topic_repository.Extension{
@@ -13,6 +22,42 @@ var meta = []core_topic_repository.Extension{
 		Topic:       TOPIC_WEB_DATA,
 		Description: "Represents the repository of user web data.",
 	},
+	{
+		Topic:       TOPIC_DEVICE,
+		Description: "Represents the repository of user device sessions.",
+	},
+	{
+		Topic:       TOPIC_FACTOR,
+		Description: "Represents the repository of user second authentication factors.",
+	},
+	{
+		Topic:       TOPIC_SETTING,
+		Description: "Represents the repository of runtime settings.",
+	},
+	{
+		Topic:       TOPIC_ACCOUNT,
+		Description: "Represents the repository of user account states.",
+	},
+	{
+		Topic:       TOPIC_ROLE,
+		Description: "Represents the repository of roles and their permissions.",
+	},
+	{
+		Topic:       TOPIC_SCOPE,
+		Description: "Represents the repository of API token scopes and restrictions.",
+	},
+	{
+		Topic:       TOPIC_AUDIT,
+		Description: "Represents the append-only repository of security audit events.",
+	},
+	{
+		Topic:       TOPIC_NAMESPACE,
+		Description: "Represents the repository of web data namespaces and their schemas.",
+	},
+	{
+		Topic:       TOPIC_WEB_REVISION,
+		Description: "Represents the repository of previous revisions of user web data.",
+	},
 }
 
 func init() {
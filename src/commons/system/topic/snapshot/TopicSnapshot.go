@@ -6,7 +6,16 @@ import (
 )
 
 const (
-	TOPIC_WEB_DATA core_topic_snapshot.TopicSnapshot = "snpsh_web"
+	TOPIC_WEB_DATA     core_topic_snapshot.TopicSnapshot = "snpsh_web"
+	TOPIC_DEVICE       core_topic_snapshot.TopicSnapshot = "snpsh_device"
+	TOPIC_FACTOR       core_topic_snapshot.TopicSnapshot = "snpsh_factor"
+	TOPIC_SETTING      core_topic_snapshot.TopicSnapshot = "snpsh_setting"
+	TOPIC_ACCOUNT      core_topic_snapshot.TopicSnapshot = "snpsh_account"
+	TOPIC_ROLE         core_topic_snapshot.TopicSnapshot = "snpsh_role"
+	TOPIC_SCOPE        core_topic_snapshot.TopicSnapshot = "snpsh_scope"
+	TOPIC_AUDIT        core_topic_snapshot.TopicSnapshot = "snpsh_audit"
+	TOPIC_NAMESPACE    core_topic_snapshot.TopicSnapshot = "snpsh_namespace"
+	TOPIC_WEB_REVISION core_topic_snapshot.TopicSnapshot = "snpsh_web_revision"
 )
 
 var meta = []core_topic_snapshot.Extension{
@@ -16,6 +25,60 @@ var meta = []core_topic_snapshot.Extension{
 		CsvPath:     "./db/snapshot/web",
 		Repository:  topic_repository.TOPIC_WEB_DATA,
 	},
+	{
+		Topic:       TOPIC_DEVICE,
+		Description: "Represents a snapshot of user device sessions.",
+		CsvPath:     "./db/snapshot/device",
+		Repository:  topic_repository.TOPIC_DEVICE,
+	},
+	{
+		Topic:       TOPIC_FACTOR,
+		Description: "Represents a snapshot of user second authentication factors.",
+		CsvPath:     "./db/snapshot/factor",
+		Repository:  topic_repository.TOPIC_FACTOR,
+	},
+	{
+		Topic:       TOPIC_SETTING,
+		Description: "Represents a snapshot of runtime settings.",
+		CsvPath:     "./db/snapshot/setting",
+		Repository:  topic_repository.TOPIC_SETTING,
+	},
+	{
+		Topic:       TOPIC_ACCOUNT,
+		Description: "Represents a snapshot of user account states.",
+		CsvPath:     "./db/snapshot/account",
+		Repository:  topic_repository.TOPIC_ACCOUNT,
+	},
+	{
+		Topic:       TOPIC_ROLE,
+		Description: "Represents a snapshot of roles and their permissions.",
+		CsvPath:     "./db/snapshot/role",
+		Repository:  topic_repository.TOPIC_ROLE,
+	},
+	{
+		Topic:       TOPIC_SCOPE,
+		Description: "Represents a snapshot of API token scopes and restrictions.",
+		CsvPath:     "./db/snapshot/scope",
+		Repository:  topic_repository.TOPIC_SCOPE,
+	},
+	{
+		Topic:       TOPIC_AUDIT,
+		Description: "Represents a snapshot of security audit events.",
+		CsvPath:     "./db/snapshot/audit",
+		Repository:  topic_repository.TOPIC_AUDIT,
+	},
+	{
+		Topic:       TOPIC_NAMESPACE,
+		Description: "Represents a snapshot of web data namespaces and their schemas.",
+		CsvPath:     "./db/snapshot/namespace",
+		Repository:  topic_repository.TOPIC_NAMESPACE,
+	},
+	{
+		Topic:       TOPIC_WEB_REVISION,
+		Description: "Represents a snapshot of previous revisions of user web data.",
+		CsvPath:     "./db/snapshot/web_revision",
+		Repository:  topic_repository.TOPIC_WEB_REVISION,
+	},
 }
 
 func init() {
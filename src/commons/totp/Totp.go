@@ -0,0 +1,100 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	Period = 30
+	Digits = 6
+	Skew   = 1
+)
+
+const secretSize = 20
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random shared secret encoded in base32, as
+// expected by authenticator applications.
+func GenerateSecret() (string, error) {
+	buffer := make([]byte, secretSize)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(buffer), nil
+}
+
+// URI builds the otpauth provisioning URI rendered as a QR code by the client.
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(Period))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+// Step returns the time step the instant belongs to.
+func Step(instant time.Time) int64 {
+	return instant.Unix() / Period
+}
+
+// Code computes the RFC 6238 code of the secret for the given time step.
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for range Digits {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%modulo), nil
+}
+
+// Validate checks the code against the steps around the instant and returns
+// the matching step, so the caller can reject codes already used.
+func Validate(secret, code string, instant time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(instant)
+	for delta := -Skew; delta <= Skew; delta++ {
+		step := current + int64(delta)
+		expected, err := Code(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,71 @@
+package account
+
+import "slices"
+
+const (
+	PROVIDER_LOCAL = "local"
+	PROVIDER_OIDC  = "oidc"
+)
+
+// Account records what the core session does not: the role changes, the
+// disabling and the pending deletion of the user. Suspended tells the account
+// was disabled by its pending deletion, so cancelling it enables the account.
+// The users of an identity provider are linked by its issuer and subject.
+type Account struct {
+	Id        string   `json:"id"`
+	Owner     string   `json:"owner"`
+	Provider  string   `json:"provider"`
+	Issuer    string   `json:"issuer"`
+	Subject   string   `json:"subject"`
+	Grants    []string `json:"grants"`
+	Revokes   []string `json:"revokes"`
+	Disabled  int64    `json:"disabled"`
+	Passwords []string `json:"passwords"`
+	Changed   int64    `json:"changed"`
+	Deletion  int64    `json:"deletion"`
+	Suspended bool     `json:"suspended"`
+	Timestamp int64    `json:"timestamp"`
+	Modified  int64    `json:"modified"`
+}
+
+func NewAccount(owner, provider string) *Account {
+	return &Account{
+		Owner:     owner,
+		Provider:  provider,
+		Grants:    make([]string, 0),
+		Revokes:   make([]string, 0),
+		Passwords: make([]string, 0),
+	}
+}
+
+// IsLinked tells whether the account belongs to the given user of an
+// identity provider.
+func (a Account) IsLinked(issuer, subject string) bool {
+	return a.Provider == PROVIDER_OIDC && a.Issuer == issuer && a.Subject == subject
+}
+
+func (a Account) IsDisabled() bool {
+	return a.Disabled > 0
+}
+
+// IsPendingDeletion tells whether the account is deleted and waits for its
+// data to be purged.
+func (a Account) IsPendingDeletion() bool {
+	return a.Deletion > 0
+}
+
+// Roles applies the grants and revokes of the account over the roles
+// assigned by the core.
+func (a Account) Roles(roles []string) []string {
+	result := make([]string, 0, len(roles)+len(a.Grants))
+	for _, v := range append(slices.Clone(roles), a.Grants...) {
+		if !slices.Contains(a.Revokes, v) && !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func (a Account) PersistenceId() string {
+	return a.Id
+}
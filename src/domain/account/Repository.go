@@ -0,0 +1,11 @@
+package account
+
+type Repository interface {
+	FindAll() []Account
+	Find(id string) (*Account, bool)
+	FindByOwner(owner string) (*Account, bool)
+	FindByIdentity(issuer, subject string) (*Account, bool)
+	Resolve(owner string, account *Account) *Account
+	Delete(account *Account) *Account
+	Close() error
+}
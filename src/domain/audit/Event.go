@@ -0,0 +1,115 @@
+package audit
+
+type Kind string
+
+const (
+	KIND_LOGIN           Kind = "login"
+	KIND_LOGIN_FAILED    Kind = "login_failed"
+	KIND_LOGOUT          Kind = "logout"
+	KIND_PASSWORD_CHANGE Kind = "password_change"
+	KIND_PASSWORD_RESET  Kind = "password_reset"
+	KIND_USER_CREATE     Kind = "user_create"
+	KIND_USER_DELETE     Kind = "user_delete"
+	KIND_USER_DISABLE    Kind = "user_disable"
+	KIND_USER_ENABLE     Kind = "user_enable"
+	KIND_USER_PURGE      Kind = "user_purge"
+	KIND_USER_RESTORE    Kind = "user_restore"
+	KIND_ROLE_GRANT      Kind = "role_grant"
+	KIND_ROLE_REVOKE     Kind = "role_revoke"
+	KIND_TOKEN_INSERT    Kind = "token_insert"
+	KIND_TOKEN_DELETE    Kind = "token_delete"
+	KIND_TOKEN_UNSCOPED  Kind = "token_unscoped"
+	KIND_CMD_EXEC        Kind = "cmd_exec"
+	KIND_MOCK_REJECTED   Kind = "mock_token_rejected"
+	KIND_TAKEOUT         Kind = "takeout"
+	KIND_TAKEOUT_IMPORT  Kind = "takeout_import"
+)
+
+var kinds = []Kind{
+	KIND_LOGIN,
+	KIND_LOGIN_FAILED,
+	KIND_LOGOUT,
+	KIND_PASSWORD_CHANGE,
+	KIND_PASSWORD_RESET,
+	KIND_USER_CREATE,
+	KIND_USER_DELETE,
+	KIND_USER_DISABLE,
+	KIND_USER_ENABLE,
+	KIND_USER_PURGE,
+	KIND_USER_RESTORE,
+	KIND_ROLE_GRANT,
+	KIND_ROLE_REVOKE,
+	KIND_TOKEN_INSERT,
+	KIND_TOKEN_DELETE,
+	KIND_TOKEN_UNSCOPED,
+	KIND_CMD_EXEC,
+	KIND_MOCK_REJECTED,
+	KIND_TAKEOUT,
+	KIND_TAKEOUT_IMPORT,
+}
+
+func Kinds() []Kind {
+	result := make([]Kind, len(kinds))
+	copy(result, kinds)
+	return result
+}
+
+func IsKind(value string) bool {
+	for _, v := range kinds {
+		if string(v) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a security relevant action. User is the account affected by the
+// action and Actor the one that performed it, both are the same for the
+// actions a user performs over itself.
+type Event struct {
+	Id        string `json:"id"`
+	Kind      Kind   `json:"kind"`
+	User      string `json:"user"`
+	Actor     string `json:"actor"`
+	Address   string `json:"address"`
+	Agent     string `json:"agent"`
+	Detail    string `json:"detail"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func NewEvent(kind Kind, actor, user, detail string) *Event {
+	return &Event{
+		Kind:   kind,
+		User:   user,
+		Actor:  actor,
+		Detail: detail,
+	}
+}
+
+func (e Event) PersistenceId() string {
+	return e.Id
+}
+
+// Filter selects the events of a query, the empty fields match every event.
+type Filter struct {
+	User string
+	Kind Kind
+	From int64
+	To   int64
+}
+
+func (f Filter) Matches(event Event) bool {
+	if f.User != "" && event.User != f.User && event.Actor != f.User {
+		return false
+	}
+	if f.Kind != "" && event.Kind != f.Kind {
+		return false
+	}
+	if f.From > 0 && event.Timestamp < f.From {
+		return false
+	}
+	if f.To > 0 && event.Timestamp > f.To {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,9 @@
+package audit
+
+// Repository is append only, the events cannot be modified nor deleted; they
+// are only pruned once they fall out of the retention.
+type Repository interface {
+	FindAll() []Event
+	Insert(event *Event) *Event
+	Close() error
+}
@@ -0,0 +1,31 @@
+package device
+
+type Device struct {
+	Id        string `json:"id"`
+	Owner     string `json:"owner"`
+	Agent     string `json:"agent"`
+	Address   string `json:"address"`
+	Refresh   string `json:"refresh"`
+	Access    string `json:"access"`
+	Timestamp int64  `json:"timestamp"`
+	LastSeen  int64  `json:"last_seen"`
+	Revoked   int64  `json:"revoked"`
+	Remember  bool   `json:"remember"`
+}
+
+func NewDevice(owner, agent, address string, remember bool) *Device {
+	return &Device{
+		Owner:    owner,
+		Agent:    agent,
+		Address:  address,
+		Remember: remember,
+	}
+}
+
+func (d Device) IsRevoked() bool {
+	return d.Revoked > 0
+}
+
+func (d Device) PersistenceId() string {
+	return d.Id
+}
@@ -0,0 +1,10 @@
+package device
+
+type Repository interface {
+	FindAll() []Device
+	Find(id string) (*Device, bool)
+	FindByOwner(owner string) []Device
+	Resolve(owner string, device *Device) *Device
+	Delete(device *Device) *Device
+	Close() error
+}
@@ -0,0 +1,27 @@
+package factor
+
+type Factor struct {
+	Id        string   `json:"id"`
+	Owner     string   `json:"owner"`
+	Secret    string   `json:"secret"`
+	Recovery  []string `json:"recovery"`
+	LastStep  int64    `json:"last_step"`
+	Timestamp int64    `json:"timestamp"`
+	Enabled   int64    `json:"enabled"`
+}
+
+func NewFactor(owner, secret string) *Factor {
+	return &Factor{
+		Owner:    owner,
+		Secret:   secret,
+		Recovery: make([]string, 0),
+	}
+}
+
+func (f Factor) IsEnabled() bool {
+	return f.Enabled > 0
+}
+
+func (f Factor) PersistenceId() string {
+	return f.Id
+}
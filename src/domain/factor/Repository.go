@@ -0,0 +1,9 @@
+package factor
+
+type Repository interface {
+	Find(id string) (*Factor, bool)
+	FindByOwner(owner string) (*Factor, bool)
+	Resolve(owner string, factor *Factor) *Factor
+	Delete(factor *Factor) *Factor
+	Close() error
+}
@@ -0,0 +1,28 @@
+package namespace
+
+// Namespace groups a part of the web data of the users. The schema, when
+// present, is the JSON Schema the value of the namespace must satisfy, and the
+// quota is its size limit in bytes (0 to fall back to the default one).
+type Namespace struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Schema      string `json:"schema"`
+	Quota       int64  `json:"quota"`
+	Timestamp   int64  `json:"timestamp"`
+	Modified    int64  `json:"modified"`
+	Owner       string `json:"owner"`
+}
+
+func NewNamespace(name, description, schema string, quota int64) *Namespace {
+	return &Namespace{
+		Name:        name,
+		Description: description,
+		Schema:      schema,
+		Quota:       quota,
+	}
+}
+
+func (n Namespace) PersistenceId() string {
+	return n.Id
+}
@@ -0,0 +1,10 @@
+package namespace
+
+type Repository interface {
+	FindAll() []Namespace
+	Find(id string) (*Namespace, bool)
+	FindByName(name string) (*Namespace, bool)
+	Resolve(owner string, namespace *Namespace) *Namespace
+	Delete(namespace *Namespace) *Namespace
+	Close() error
+}
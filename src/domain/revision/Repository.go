@@ -0,0 +1,8 @@
+package revision
+
+type Repository interface {
+	FindByOwner(owner string) []Revision
+	Insert(revision *Revision) *Revision
+	Delete(revisions ...Revision) []Revision
+	Close() error
+}
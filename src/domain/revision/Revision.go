@@ -0,0 +1,34 @@
+package revision
+
+import "github.com/Rafael24595/go-api-render/src/domain/web"
+
+// Revision is a version of the web data of a user that has been replaced. The
+// number grows with every revision of the owner, the size is the one of the
+// version in bytes and the change is how much the next version grew or shrank.
+type Revision struct {
+	Id         string               `json:"id"`
+	Owner      string               `json:"owner"`
+	Number     int64                `json:"number"`
+	Data       map[string]string    `json:"data"`
+	Namespaces map[string]web.Value `json:"namespaces"`
+	Size       int64                `json:"size"`
+	Change     int64                `json:"change"`
+	Modified   int64                `json:"modified"`
+	Timestamp  int64                `json:"timestamp"`
+}
+
+func NewRevision(number int64, webData web.WebData, size, change int64) *Revision {
+	return &Revision{
+		Owner:      webData.Owner,
+		Number:     number,
+		Data:       webData.Data,
+		Namespaces: webData.Namespaces,
+		Size:       size,
+		Change:     change,
+		Modified:   webData.Modified,
+	}
+}
+
+func (r Revision) PersistenceId() string {
+	return r.Id
+}
@@ -0,0 +1,45 @@
+package role
+
+type Permission string
+
+const (
+	PERMISSION_NONE              Permission = ""
+	PERMISSION_COLLECTIONS_READ  Permission = "collections:read"
+	PERMISSION_COLLECTIONS_WRITE Permission = "collections:write"
+	PERMISSION_MOCK_MANAGE       Permission = "mock:manage"
+	PERMISSION_TOKENS_MANAGE     Permission = "tokens:manage"
+	PERMISSION_USERS_MANAGE      Permission = "users:manage"
+	PERMISSION_ROLES_MANAGE      Permission = "roles:manage"
+	PERMISSION_SYSTEM_LOG        Permission = "system:log"
+	PERMISSION_SYSTEM_CMD        Permission = "system:cmd"
+	PERMISSION_SYSTEM_AUDIT      Permission = "system:audit"
+	PERMISSION_WEB_MANAGE        Permission = "web:manage"
+)
+
+var permissions = []Permission{
+	PERMISSION_COLLECTIONS_READ,
+	PERMISSION_COLLECTIONS_WRITE,
+	PERMISSION_MOCK_MANAGE,
+	PERMISSION_TOKENS_MANAGE,
+	PERMISSION_USERS_MANAGE,
+	PERMISSION_ROLES_MANAGE,
+	PERMISSION_SYSTEM_LOG,
+	PERMISSION_SYSTEM_CMD,
+	PERMISSION_SYSTEM_AUDIT,
+	PERMISSION_WEB_MANAGE,
+}
+
+func Permissions() []Permission {
+	result := make([]Permission, len(permissions))
+	copy(result, permissions)
+	return result
+}
+
+func IsPermission(value string) bool {
+	for _, v := range permissions {
+		if string(v) == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+package role
+
+type Repository interface {
+	FindAll() []Role
+	Find(id string) (*Role, bool)
+	FindByName(name string) (*Role, bool)
+	Resolve(owner string, role *Role) *Role
+	Delete(role *Role) *Role
+	Close() error
+}
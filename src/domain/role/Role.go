@@ -0,0 +1,36 @@
+package role
+
+import "slices"
+
+// ROLE_USER is the role every user holds besides the ones assigned to it.
+const ROLE_USER = "user"
+
+type Role struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	Timestamp   int64    `json:"timestamp"`
+	Modified    int64    `json:"modified"`
+	Owner       string   `json:"owner"`
+}
+
+func NewRole(name, description string, permissions ...Permission) *Role {
+	role := &Role{
+		Name:        name,
+		Description: description,
+		Permissions: make([]string, 0, len(permissions)),
+	}
+	for _, v := range permissions {
+		role.Permissions = append(role.Permissions, string(v))
+	}
+	return role
+}
+
+func (r Role) HasPermission(permission Permission) bool {
+	return slices.Contains(r.Permissions, string(permission))
+}
+
+func (r Role) PersistenceId() string {
+	return r.Id
+}
@@ -0,0 +1,51 @@
+package scope
+
+import "slices"
+
+// Policy holds the scopes and the resource restrictions of an API token. A
+// token without a policy keeps the full access of its owner: new API tokens
+// always get one, so only the tokens created before the scopes existed lack
+// it.
+type Policy struct {
+	Id          string   `json:"id"`
+	Token       string   `json:"token"`
+	Scopes      []string `json:"scopes"`
+	Collections []string `json:"collections"`
+	EndPoints   []string `json:"endpoints"`
+	Timestamp   int64    `json:"timestamp"`
+	Modified    int64    `json:"modified"`
+	Owner       string   `json:"owner"`
+}
+
+func NewPolicy(token string, scopes, collections, endPoints []string) *Policy {
+	return &Policy{
+		Token:       token,
+		Scopes:      scopes,
+		Collections: collections,
+		EndPoints:   endPoints,
+	}
+}
+
+func (p Policy) Allows(resource Resource, access Access) bool {
+	if slices.Contains(p.Scopes, string(Of(resource, access))) {
+		return true
+	}
+	return access == ACCESS_READ && slices.Contains(p.Scopes, string(Of(resource, ACCESS_WRITE)))
+}
+
+// Restricts returns the identifiers the token is limited to for the resource,
+// or nothing if it may address any of them.
+func (p Policy) Restricts(resource Resource) []string {
+	switch resource {
+	case RESOURCE_COLLECTIONS:
+		return p.Collections
+	case RESOURCE_MOCK:
+		return p.EndPoints
+	default:
+		return nil
+	}
+}
+
+func (p Policy) PersistenceId() string {
+	return p.Id
+}
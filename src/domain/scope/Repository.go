@@ -0,0 +1,10 @@
+package scope
+
+type Repository interface {
+	FindAll() []Policy
+	Find(id string) (*Policy, bool)
+	FindByToken(token string) (*Policy, bool)
+	Resolve(owner string, policy *Policy) *Policy
+	Delete(policy *Policy) *Policy
+	Close() error
+}
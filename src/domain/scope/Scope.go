@@ -0,0 +1,54 @@
+package scope
+
+import "slices"
+
+type Resource string
+
+const (
+	RESOURCE_COLLECTIONS Resource = "collections"
+	RESOURCE_HISTORIC    Resource = "historic"
+	RESOURCE_MOCK        Resource = "mock"
+)
+
+type Access string
+
+const (
+	ACCESS_READ  Access = "read"
+	ACCESS_WRITE Access = "write"
+)
+
+// Scope limits an API token to an access level over a resource, such as
+// "collections:read". The write access includes the read access.
+type Scope string
+
+func Of(resource Resource, access Access) Scope {
+	return Scope(string(resource) + ":" + string(access))
+}
+
+var resources = []Resource{
+	RESOURCE_COLLECTIONS,
+	RESOURCE_HISTORIC,
+	RESOURCE_MOCK,
+}
+
+func Scopes() []Scope {
+	result := make([]Scope, 0, len(resources)*2)
+	for _, v := range resources {
+		result = append(result, Of(v, ACCESS_READ), Of(v, ACCESS_WRITE))
+	}
+	return result
+}
+
+// Defaults returns the scopes of an API token created without any: the read
+// access of every resource.
+func Defaults() []Scope {
+	result := make([]Scope, 0, len(resources))
+	for _, v := range resources {
+		result = append(result, Of(v, ACCESS_READ))
+	}
+	return result
+}
+
+func IsScope(value string) bool {
+	return slices.Contains(Scopes(), Scope(value))
+}
@@ -0,0 +1,9 @@
+package setting
+
+type Repository interface {
+	FindAll() []Setting
+	Find(id string) (*Setting, bool)
+	Resolve(owner string, setting *Setting) *Setting
+	Delete(setting *Setting) *Setting
+	Close() error
+}
@@ -0,0 +1,23 @@
+package setting
+
+type Setting struct {
+	Id       string `json:"id"`
+	Value    string `json:"value"`
+	Modified int64  `json:"modified"`
+	Owner    string `json:"owner"`
+}
+
+func NewSetting(id, value string) *Setting {
+	return &Setting{
+		Id:    id,
+		Value: value,
+	}
+}
+
+func (s Setting) PersistenceId() string {
+	return s.Id
+}
+
+const (
+	AUTH_2FA_ADMINS = "auth.2fa.admins"
+)
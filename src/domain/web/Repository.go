@@ -3,6 +3,8 @@ package web
 type Repository interface {
 	Find(id string) (*WebData, bool)
 	FindByOwner(owner string) (*WebData, bool)
-	Resolve(owner string, webData *WebData) *WebData
-	Delete(token *WebData) *WebData
+	Resolve(owner string, webData *WebData) (*WebData, error)
+	Delete(token *WebData) (*WebData, error)
+	Flush() error
+	Close() error
 }
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// Value holds the JSON text of the data of a namespace, so any JSON value can
+// be stored while the persistence keeps seeing plain strings.
+type Value string
+
+func MakeValue(raw []byte) (Value, error) {
+	if !json.Valid(raw) {
+		return "", errors.New("the value is not valid JSON")
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := json.Compact(buffer, raw); err != nil {
+		return "", err
+	}
+
+	return Value(buffer.String()), nil
+}
+
+func (v Value) IsNull() bool {
+	return v == "" || v == "null"
+}
+
+func (v Value) Decode() (any, error) {
+	var result any
+	if v.IsNull() {
+		return result, nil
+	}
+	err := json.Unmarshal([]byte(v), &result)
+	return result, err
+}
+
+// Merge applies a JSON Merge Patch (RFC 7396) to the value.
+func (v Value) Merge(patch []byte) (Value, error) {
+	var change any
+	if err := json.Unmarshal(patch, &change); err != nil {
+		return "", err
+	}
+
+	current, err := v.Decode()
+	if err != nil {
+		return "", err
+	}
+
+	merged, err := json.Marshal(mergePatch(current, change))
+	if err != nil {
+		return "", err
+	}
+
+	return Value(merged), nil
+}
+
+func mergePatch(target, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	result, ok := target.(map[string]any)
+	if !ok {
+		result = make(map[string]any)
+	}
+
+	for k, v := range changes {
+		if v == nil {
+			delete(result, k)
+			continue
+		}
+		result[k] = mergePatch(result[k], v)
+	}
+
+	return result
+}
+
+func (v Value) MarshalJSON() ([]byte, error) {
+	if v == "" {
+		return []byte("null"), nil
+	}
+	return []byte(v), nil
+}
+
+func (v *Value) UnmarshalJSON(raw []byte) error {
+	value, err := MakeValue(raw)
+	if err != nil {
+		return err
+	}
+	*v = value
+	return nil
+}
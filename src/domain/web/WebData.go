@@ -1,22 +1,56 @@
 package web
 
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+)
+
+// WebData keeps the preferences of the front end. The flat data predates the
+// namespaces, which hold any JSON value and may be validated by a schema.
+// Revision is the number of the last revision kept of the data.
 type WebData struct {
-	Id        string            `json:"id"`
-	Timestamp int64             `json:"timestamp"`
-	Data      map[string]string `json:"data"`
-	Modified  int64             `json:"modified"`
-	Owner     string            `json:"owner"`
+	Id         string            `json:"id"`
+	Timestamp  int64             `json:"timestamp"`
+	Data       map[string]string `json:"data"`
+	Namespaces map[string]Value  `json:"namespaces"`
+	Modified   int64             `json:"modified"`
+	Revision   int64             `json:"revision"`
+	Owner      string            `json:"owner"`
 }
 
 func EmptyWebData(owner string) *WebData {
 	return &WebData{
 		Timestamp: 0,
 		Data: make(map[string]string),
+		Namespaces: make(map[string]Value),
 		Modified: 0,
 		Owner: owner,
 	}
 }
 
+// Content encodes the data and the namespaces, the part of the data that
+// tells its versions apart.
+func (r WebData) Content() ([]byte, error) {
+	return json.Marshal(struct {
+		Data       map[string]string `json:"data"`
+		Namespaces map[string]Value  `json:"namespaces"`
+	}{r.Data, r.Namespaces})
+}
+
+// ETag identifies the version of the data by a hash of its content, so two
+// changes within the same millisecond still get different tags.
+func (r WebData) ETag() string {
+	content, err := r.Content()
+	if err != nil {
+		return strconv.Quote(strconv.FormatInt(r.Modified, 10))
+	}
+
+	sum := sha256.Sum256(content)
+	return strconv.Quote(base64.RawURLEncoding.EncodeToString(sum[:]))
+}
+
 func (r WebData) PersistenceId() string {
 	return r.Id
 }
@@ -1,9 +1,15 @@
 package controller
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"net"
 	"net/http"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Rafael24595/go-api-core/src/application/manager"
@@ -16,24 +22,43 @@ import (
 	render_manager "github.com/Rafael24595/go-api-render/src/application/manager"
 	auth "github.com/Rafael24595/go-api-render/src/commons/auth/Jwt.go"
 	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/commons/oidc"
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+	"github.com/Rafael24595/go-log/log"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
 )
 
 const USER = "user"
+const SESSION = "session"
+const API_TOKEN = "api_token"
+
+const AUTH_HEADER = "Authorization"
+const AUTH_HEADER_DESCRIPTION = "Bearer scheme with a session JWT or an API token. Takes precedence over the other credentials"
+
+const API_KEY_HEADER = "X-API-Key"
+const API_KEY_HEADER_DESCRIPTION = "API token. Takes precedence over the cookies"
+
+const BEARER_SCHEME = "Bearer"
 
 const (
-	AUTH_401 = "Invalid or expired authentication token"
-	AUTH_404 = "User does not exist or session is invalid"
-	AUTH_406 = "Password update required"
+	AUTH_401      = "Invalid or expired authentication token"
+	AUTH_403      = "Two-factor authentication required or CSRF token mismatch"
+	AUTH_403_CSRF = "CSRF token missing or mismatch"
+	AUTH_404      = "User does not exist or session is invalid"
+	AUTH_406      = "Password update required"
 )
 
 const BASE_PATH = "/api/v1/"
 
 type Controller struct {
-	router       *router.Router
-	managerToken *manager.ManagerToken
+	router          *router.Router
+	managerToken    *manager.ManagerToken
+	managerFactor   *render_manager.ManagerFactor
+	managerDevice   *render_manager.ManagerDevice
+	managerAccount  *render_manager.ManagerAccount
+	managerPassword *render_manager.ManagerPassword
 }
 
 func NewController(
@@ -48,19 +73,41 @@ func NewController(
 	managerToken *manager.ManagerToken,
 	managerSessionData *session.ManagerSessionData,
 	managerWeb *render_manager.ManagerWeb,
+	managerDevice *render_manager.ManagerDevice,
+	managerFactor *render_manager.ManagerFactor,
+	managerLockout *render_manager.ManagerLockout,
+	managerAccount *render_manager.ManagerAccount,
+	managerRole *render_manager.ManagerRole,
+	managerScope *render_manager.ManagerScope,
+	managerAudit *render_manager.ManagerAudit,
+	managerPassword *render_manager.ManagerPassword,
+	managerNamespace *render_manager.ManagerNamespace,
+	managerTakeout *render_manager.ManagerTakeout,
+	managerDeletion *render_manager.ManagerDeletion,
 ) Controller {
 	conf := configuration.Instance()
 
 	instance := Controller{
-		router:       route,
-		managerToken: managerToken,
+		router:          route,
+		managerToken:    managerToken,
+		managerFactor:   managerFactor,
+		managerDevice:   managerDevice,
+		managerAccount:  managerAccount,
+		managerPassword: managerPassword,
 	}
 
+	secure := NewSecureRouter(route, managerRole, managerAccount, managerScope, managerAudit)
+
 	if conf.Front.Enabled {
-		NewControllerFront(route)
+		NewControllerFront(secure)
 	}
 
+	NewControllerWellKnown(secure)
+
 	laxAuth := instance.laxAuth
+	if conf.ClientCert().Enabled() {
+		laxAuth = router.FallbackHandlers(instance.authCert, laxAuth)
+	}
 	if conf.EnableUserToken() {
 		laxAuth = router.FallbackHandlers(instance.authToken, laxAuth)
 	}
@@ -74,10 +121,13 @@ func NewController(
 			"user/verify",
 		).
 		GroupContextualizerDocument(strictAuth, docAuthStrict,
+			"admin",
 			"system/log",
 			"system/cmd",
+			"system/audit",
 			"action",
 			"import",
+			"export",
 			"sort",
 			"context",
 			"historic",
@@ -88,56 +138,98 @@ func NewController(
 			"mock/endpoint",
 			"mock/metrics/",
 			"bridge/mock/endpoint",
-		).
-		Cors(router.PermissiveCors())
+			"bridge/mock/response",
+		)
 
 	if configuration.Instance().Dev() {
-		NewControllerDev(route)
+		NewControllerDev(secure)
+		if standIn, err := oidc.NewStandIn(conf.Oidc()); err == nil {
+			NewControllerDevOidc(secure, standIn)
+		} else {
+			log.Error(err)
+		}
 	}
 
 	if configuration.Instance().EnableSecrets() {
-		NewControllerSecret(route)
+		NewControllerSecret(secure)
 	}
 
-	NewControllerSystem(route)
-	NewControllerLogin(route, managerWeb)
-	NewControllerActions(route)
-	NewControllerRequest(route, managerRequest, managerCollection, managerSessionData)
-	NewControllerHistoric(route, managerRequest, managerHisotric, managerSessionData)
-	NewControllerContext(route, managerContext, managerSessionData)
-	NewControllerCollection(route, managerCollection, managerGroup, managerSessionData)
-	NewControllerCurl(route, managerRequest, managerCollection, managerGroup,
+	NewControllerSystem(secure, managerAudit)
+	NewControllerLogin(secure, managerWeb, managerDevice, managerFactor, managerLockout, managerAccount, managerAudit, managerPassword, managerDeletion)
+	NewControllerSession(secure, managerDevice)
+	NewControllerFactor(secure, managerFactor, managerAccount)
+	NewControllerLockout(secure, managerLockout)
+	NewControllerUser(secure, managerDevice, managerAccount, managerRole, managerAudit, managerPassword, managerDeletion)
+	NewControllerRole(secure, managerRole, managerAccount)
+	NewControllerNamespace(secure, managerNamespace)
+	NewControllerTakeout(secure, managerTakeout, managerAudit)
+	if conf.Oidc().Enabled() {
+		NewControllerOidc(secure, oidc.NewProvider(conf.Oidc()), managerDevice, managerAccount, managerAudit)
+	}
+	NewControllerActions(secure)
+	NewControllerRequest(secure, managerRequest, managerCollection, managerSessionData)
+	NewControllerHistoric(secure, managerRequest, managerHisotric, managerSessionData)
+	NewControllerContext(secure, managerContext, managerSessionData)
+	NewControllerCollection(secure, managerCollection, managerGroup, managerSessionData)
+	NewControllerCurl(secure, managerRequest, managerCollection, managerGroup,
 		managerContext, managerEndPoint, managerSessionData)
-	NewControllerMock(route, managerToken, managerEndPoint, managerMetrics)
-	NewControllerToken(route, managerToken)
+	NewControllerMock(secure, managerToken, managerEndPoint, managerMetrics, managerAudit)
+	NewControllerToken(secure, managerToken, managerScope, managerAudit)
 
 	return instance
 }
 
+// Handler wraps the router with the checks every request goes through,
+// whatever route it reaches: the CORS policy and the CSRF token of the
+// requests authenticated by cookie.
+func Handler(route *router.Router) http.Handler {
+	return cors(csrf(route))
+}
+
+// Credentials are resolved in the following order, and only the first one
+// present is considered:
+//
+//  1. The Authorization header with the Bearer scheme. JWT shaped values are
+//     validated as session tokens and any other value as an API token.
+//  2. The X-API-Key header, validated as an API token.
+//  3. The go_user_token cookie (API token) falling back to the go_api_token
+//     cookie (session token), as browsers send both.
+//  4. The client certificate of the TLS connection, verified against the
+//     configured CA bundle.
+//
+// API tokens are only accepted when user tokens are enabled, and client
+// certificates when they are configured.
 var docAuthLax = docs.DocGroup{
+	Headers: docs.DocParameters{
+		AUTH_HEADER:    AUTH_HEADER_DESCRIPTION,
+		API_KEY_HEADER: API_KEY_HEADER_DESCRIPTION,
+		CSRF_HEADER:    CSRF_HEADER_DESCRIPTION,
+	},
 	Cookies: docs.DocParameters{
 		AUTH_COOKIE: AUTH_COOKIE_DESCRIPTION,
 		AUTH_TOKEN:  AUTH_TOKEN_DESCRIPTION,
+		CSRF_COOKIE: CSRF_COOKIE_DESCRIPTION,
 	},
 	Responses: docs.DocResponses{
 		"401": docs.DocText(AUTH_401),
+		"403": docs.DocText(AUTH_403_CSRF),
 		"404": docs.DocText(AUTH_404),
 	},
 }
 
 func (c *Controller) authToken(w http.ResponseWriter, r *http.Request, context *router.Context) result.Result {
-	cookie, err := r.Cookie(AUTH_TOKEN)
-	if err != nil {
-		return result.Err(http.StatusUnauthorized, err)
-	}
-
-	if cookie == nil {
+	credential, ok := findApiToken(r)
+	if !ok {
 		return result.Reject(http.StatusUnauthorized)
 	}
 
-	tkn, ok := c.managerToken.FindGlobal(cookie.Value)
+	tkn, ok := c.managerToken.FindGlobal(credential.value)
 	if !ok {
-		return result.Err(http.StatusForbidden, err)
+		return result.Reject(http.StatusForbidden)
+	}
+
+	if c.managerAccount.IsDisabled(tkn.Owner) {
+		return result.TextErr(http.StatusForbidden, "the account is disabled")
 	}
 
 	if tkn.IsExipred() {
@@ -149,6 +241,37 @@ func (c *Controller) authToken(w http.ResponseWriter, r *http.Request, context *
 	}
 
 	context.Put(USER, tkn.Owner)
+	context.Put(API_TOKEN, tkn.Id)
+
+	return result.Ok(context)
+}
+
+// authCert authenticates the user named by the verified client certificate of
+// the connection. Requests carrying a session token are left to laxAuth.
+func (c *Controller) authCert(w http.ResponseWriter, r *http.Request, context *router.Context) result.Result {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return result.Reject(http.StatusUnauthorized)
+	}
+
+	if _, ok := findSessionToken(r); ok {
+		return result.Reject(http.StatusUnauthorized)
+	}
+
+	user, ok := configuration.Instance().ClientCert().Username(r.TLS.VerifiedChains[0][0])
+	if !ok {
+		return result.TextErr(http.StatusForbidden, "the client certificate does not name a user")
+	}
+
+	sessions := session.InstanceManagerSession()
+	if _, exists := sessions.Find(user); !exists {
+		return result.TextErr(http.StatusForbidden, "the client certificate does not match any user")
+	}
+
+	if c.managerAccount.IsDisabled(user) {
+		return result.TextErr(http.StatusForbidden, "the account is disabled")
+	}
+
+	context.Put(USER, user)
 
 	return result.Ok(context)
 }
@@ -156,21 +279,30 @@ func (c *Controller) authToken(w http.ResponseWriter, r *http.Request, context *
 func (c *Controller) laxAuth(w http.ResponseWriter, r *http.Request, context *router.Context) result.Result {
 	user := action.ANONYMOUS_OWNER
 
-	token, err := r.Cookie(AUTH_COOKIE)
-	if err != nil {
+	token, ok := findSessionToken(r)
+	if !ok {
 		context.Put(USER, user)
 		return result.Ok(context)
 	}
 
-	claims, err := auth.ValidateJWT(token.Value)
+	claims, err := auth.ValidateJWT(token.value, auth.TOKEN_ACCESS)
 	if err != nil {
-		closeSession(w)
-		if claims != nil && 0 >= time.Until(claims.ExpiresAt.Time) {
+		if token.cookie {
+			closeSession(w)
+		}
+		if auth.IsExpired(err) {
 			return result.Err(498, errors.New("token expired"))
 		}
 		return result.Err(http.StatusUnauthorized, err)
 	}
 
+	if auth.IsDenied(claims.ID, claims.Session) {
+		if token.cookie {
+			closeSession(w)
+		}
+		return result.TextErr(http.StatusUnauthorized, "the session has been revoked")
+	}
+
 	user = claims.Username
 
 	sessions := session.InstanceManagerSession()
@@ -180,17 +312,69 @@ func (c *Controller) laxAuth(w http.ResponseWriter, r *http.Request, context *ro
 		return result.Err(http.StatusNotFound, err)
 	}
 
+	if c.managerAccount.IsDisabled(user) {
+		if token.cookie {
+			eraseSession(w)
+		}
+		return result.TextErr(http.StatusForbidden, "the account is disabled")
+	}
+
+	if res := c.touchSession(w, user, claims, token.cookie); res != nil {
+		return *res
+	}
+
 	context.Put(USER, user)
+	context.Put(SESSION, claims.Session)
 
 	return result.Ok(context)
 }
 
+// touchSession checks the session of the token is still alive and slides its
+// idle expiration. Sessions past their idle or absolute lifetime are closed.
+// Only the access token last issued for the session is accepted.
+func (c *Controller) touchSession(w http.ResponseWriter, user string, claims *auth.Claims, cookie bool) *result.Result {
+	device, ok := c.managerDevice.Find(user, claims.Session)
+	if !ok || device.IsRevoked() {
+		if cookie {
+			closeSession(w)
+		}
+		res := result.TextErr(http.StatusUnauthorized, "the session has been revoked")
+		return &res
+	}
+
+	if c.managerDevice.IsExpired(device) {
+		c.managerDevice.Revoke(user, device)
+		if cookie {
+			eraseSession(w)
+		}
+		res := result.TextErr(http.StatusUnauthorized, "the session has expired")
+		return &res
+	}
+
+	if claims.ID != device.Access {
+		res := result.Err(498, errors.New("token superseded"))
+		return &res
+	}
+
+	c.managerDevice.Touch(user, device)
+
+	return nil
+}
+
 var docAuthStrict = docs.DocGroup{
+	Headers: docs.DocParameters{
+		AUTH_HEADER:    AUTH_HEADER_DESCRIPTION,
+		API_KEY_HEADER: API_KEY_HEADER_DESCRIPTION,
+		CSRF_HEADER:    CSRF_HEADER_DESCRIPTION,
+	},
 	Cookies: docs.DocParameters{
 		AUTH_COOKIE: AUTH_COOKIE_DESCRIPTION,
+		AUTH_TOKEN:  AUTH_TOKEN_DESCRIPTION,
+		CSRF_COOKIE: CSRF_COOKIE_DESCRIPTION,
 	},
 	Responses: docs.DocResponses{
 		"401": docs.DocText(AUTH_401),
+		"403": docs.DocText(AUTH_403),
 		"404": docs.DocText(AUTH_404),
 		"406": docs.DocText(AUTH_406),
 	},
@@ -209,31 +393,161 @@ func (c *Controller) authStrict(w http.ResponseWriter, r *http.Request, context
 
 	sessions := session.InstanceManagerSession()
 
-	session, exists := sessions.Find(username)
+	found, exists := sessions.Find(username)
 	if !exists {
 		return result.Reject(http.StatusNotFound)
 	}
 
-	if session.IsNotVerified() {
+	session := c.managerAccount.Resolve(found)
+
+	if session.IsNotVerified() || c.managerPassword.IsExpired(username) {
 		return result.Err(http.StatusNotAcceptable, errors.New("password update required"))
 	}
 
+	if c.managerFactor.IsRequired(session) && !c.managerFactor.IsEnabled(username) {
+		return result.TextErr(http.StatusForbidden, "two-factor authentication required")
+	}
+
 	return result.Ok(context)
 }
 
+type credential struct {
+	value  string
+	cookie bool
+}
+
+// findSessionToken returns the session JWT following the precedence described
+// in docAuthLax.
+func findSessionToken(r *http.Request) (credential, bool) {
+	if bearer, ok := findBearer(r); ok {
+		return credential{value: bearer}, isJwt(bearer)
+	}
+
+	if r.Header.Get(API_KEY_HEADER) != "" {
+		return credential{}, false
+	}
+
+	cookie, err := readCookie(r, AUTH_COOKIE, ROOT_PATH)
+	if err != nil {
+		return credential{}, false
+	}
+
+	return credential{value: cookie.Value, cookie: true}, true
+}
+
+// findApiToken returns the API token following the precedence described in
+// docAuthLax.
+func findApiToken(r *http.Request) (credential, bool) {
+	if bearer, ok := findBearer(r); ok {
+		return credential{value: bearer}, bearer != "" && !isJwt(bearer)
+	}
+
+	if key := strings.TrimSpace(r.Header.Get(API_KEY_HEADER)); key != "" {
+		return credential{value: key}, true
+	}
+
+	cookie, err := readCookie(r, AUTH_TOKEN, ROOT_PATH)
+	if err != nil {
+		return credential{}, false
+	}
+
+	return credential{value: cookie.Value, cookie: true}, true
+}
+
+func findBearer(r *http.Request) (string, bool) {
+	header := r.Header.Get(AUTH_HEADER)
+	if header == "" {
+		return "", false
+	}
+
+	scheme, value, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, BEARER_SCHEME) {
+		return "", true
+	}
+
+	return strings.TrimSpace(value), true
+}
+
+func isJwt(value string) bool {
+	return value != "" && strings.Count(value, ".") == 2
+}
+
 func findUser(ctx *router.Context) string {
 	return ctx.Getz(USER).
 		Stringd(action.ANONYMOUS_OWNER)
 }
 
-func findSession(user string) (*domain_session.Session, *result.Result) {
+func findSessionId(ctx *router.Context) string {
+	return ctx.Getz(SESSION).
+		Stringd("")
+}
+
+func findApiTokenId(ctx *router.Context) string {
+	return ctx.Getz(API_TOKEN).
+		Stringd("")
+}
+
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// recordEvent appends a security event to the audit trail with the client
+// data of the request.
+func recordEvent(managerAudit *render_manager.ManagerAudit, r *http.Request, kind audit.Kind, actor, user, detail string) {
+	event := audit.NewEvent(kind, actor, user, detail)
+	event.Address = clientAddress(r)
+	event.Agent = r.UserAgent()
+	managerAudit.Record(event)
+}
+
+func retryAfter(w http.ResponseWriter, remaining time.Duration) result.Result {
+	seconds := int(math.Ceil(remaining.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	return result.TextErr(http.StatusTooManyRequests, fmt.Sprintf("too many failed attempts, retry in %d seconds", seconds))
+}
+
+// deletionResult answers a deletion with its report, as accepted while the
+// purge is pending. A purge that left artifacts behind fails with the report
+// of what it did remove.
+func deletionResult(w http.ResponseWriter, report *render_manager.DeletionReport, incomplete *render_manager.DeletionIncomplete) result.Result {
+	if incomplete != nil {
+		return jsonStatus(w, http.StatusInternalServerError, incomplete)
+	}
+	if !report.Purged {
+		return jsonStatus(w, http.StatusAccepted, report)
+	}
+	return result.JsonOk(report)
+}
+
+// deletionDetail describes a deletion for the audit log, flagging a purge that
+// left artifacts behind.
+func deletionDetail(report *render_manager.DeletionReport, incomplete *render_manager.DeletionIncomplete) string {
+	if incomplete != nil {
+		return fmt.Sprintf("incomplete: %s; %s", incomplete.Error(), report.Summary())
+	}
+	return report.Summary()
+}
+
+func jsonStatus(w http.ResponseWriter, status int, payload any) result.Result {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+	return result.Continue()
+}
+
+// findSession returns the session of the user with its effective roles.
+func findSession(managerAccount *render_manager.ManagerAccount, user string) (*domain_session.Session, *result.Result) {
 	sessions := session.InstanceManagerSession()
 	session, ok := sessions.Find(user)
 	if !ok {
 		result := result.Reject(http.StatusUnauthorized)
 		return nil, &result
 	}
-	return session, nil
+	return managerAccount.Resolve(session), nil
 }
 
 func findTransientCollection(user string, client *session.ManagerSessionData) (*collection.Collection, *result.Result) {
@@ -7,6 +7,7 @@ import (
 	core_infrastructure "github.com/Rafael24595/go-api-core/src/infrastructure"
 
 	"github.com/Rafael24595/go-api-core/src/infrastructure/dto"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
@@ -16,16 +17,16 @@ const ID_REQUEST = "id_request"
 const ID_REQUEST_DESCRIPTION = "Request ID"
 
 type ControllerActions struct {
-	router *router.Router
+	router *SecureRouter
 }
 
-func NewControllerActions(router *router.Router) ControllerActions {
+func NewControllerActions(router *SecureRouter) ControllerActions {
 	instance := ControllerActions{
 		router: router,
 	}
 
 	router.
-		RouteDocument(http.MethodPost, instance.action, "action", instance.docAction())
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_WRITE, instance.action, "action", instance.docAction())
 
 	return instance
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/Rafael24595/go-api-core/src/application/session"
 	"github.com/Rafael24595/go-api-core/src/domain/collection"
 	"github.com/Rafael24595/go-api-core/src/infrastructure/dto"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
@@ -19,14 +20,14 @@ const ACTION = "action"
 const TARGET = "target"
 
 type ControllerCollection struct {
-	router             *router.Router
+	router             *SecureRouter
 	managerCollection  *manager.ManagerCollection
 	managerGroup       *manager.ManagerGroup
 	managerSessionData *session.ManagerSessionData
 }
 
 func NewControllerCollection(
-	router *router.Router,
+	router *SecureRouter,
 	managerCollection *manager.ManagerCollection,
 	managerGroup *manager.ManagerGroup,
 	managerSessionData *session.ManagerSessionData,
@@ -39,24 +40,24 @@ func NewControllerCollection(
 	}
 
 	instance.router.
-		RouteDocument(http.MethodPut, instance.sort, "sort/collection", instance.docSort()).
-		RouteDocument(http.MethodPut, instance.sortRequests, "sort/collection/{%s}/request", instance.docSortRequests()).
+		RouteDocument(http.MethodPut, role.PERMISSION_COLLECTIONS_WRITE, instance.sort, "sort/collection", instance.docSort()).
+		RouteDocument(http.MethodPut, role.PERMISSION_COLLECTIONS_WRITE, instance.sortRequests, "sort/collection/{%s}/request", instance.docSortRequests()).
 		//
-		RouteDocument(http.MethodGet, instance.exportAll, "export/collection", instance.docExportAll()).
-		RouteDocument(http.MethodPost, instance.exportMany, "export/collection", instance.docExportMany()).
-		RouteDocument(http.MethodPost, instance.openApi, "import/openapi", instance.docOpenApi()).
-		RouteDocument(http.MethodPost, instance.importItems, "import/collection", instance.docImportItems()).
-		RouteDocument(http.MethodPost, instance.importTo, "import/collection/{%s}", instance.docImportTo()).
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.exportAll, "export/collection", instance.docExportAll()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_READ, instance.exportMany, "export/collection", instance.docExportMany()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_WRITE, instance.openApi, "import/openapi", instance.docOpenApi()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_WRITE, instance.importItems, "import/collection", instance.docImportItems()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_WRITE, instance.importTo, "import/collection/{%s}", instance.docImportTo()).
 		//
-		RouteDocument(http.MethodGet, instance.findAll, "collection", instance.docFindAll()).
-		RouteDocument(http.MethodGet, instance.find, "collection/{%s}", instance.docFind()).
-		RouteDocument(http.MethodGet, instance.findLite, "collection/{%s}/lite", instance.docFindLite()).
-		RouteDocument(http.MethodPost, instance.insert, "collection", instance.docInsert()).
-		RouteDocument(http.MethodDelete, instance.delete, "collection/{%s}", instance.docDelete()).
-		RouteDocument(http.MethodPost, instance.clone, "collection/{%s}/clone", instance.docClone()).
-		RouteDocument(http.MethodPut, instance.collect, "collection", instance.docCollect()).
-		RouteDocument(http.MethodPut, instance.take, "collection/{%s}/request/{%s}", instance.docTake()).
-		RouteDocument(http.MethodDelete, instance.deleteFrom, "collection/{%s}/request/{%s}", instance.docDeleteFrom())
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.findAll, "collection", instance.docFindAll()).
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.find, "collection/{%s}", instance.docFind()).
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.findLite, "collection/{%s}/lite", instance.docFindLite()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_WRITE, instance.insert, "collection", instance.docInsert()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_COLLECTIONS_WRITE, instance.delete, "collection/{%s}", instance.docDelete()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_WRITE, instance.clone, "collection/{%s}/clone", instance.docClone()).
+		RouteDocument(http.MethodPut, role.PERMISSION_COLLECTIONS_WRITE, instance.collect, "collection", instance.docCollect()).
+		RouteDocument(http.MethodPut, role.PERMISSION_COLLECTIONS_WRITE, instance.take, "collection/{%s}/request/{%s}", instance.docTake()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_COLLECTIONS_WRITE, instance.deleteFrom, "collection/{%s}/request/{%s}", instance.docDeleteFrom())
 
 	return instance
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/Rafael24595/go-api-core/src/application/manager"
 	"github.com/Rafael24595/go-api-core/src/application/session"
 	"github.com/Rafael24595/go-api-core/src/infrastructure/dto"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
@@ -15,13 +16,13 @@ const ID_CONTEXT = "id_context"
 const ID_CONTEXT_DESCRIPTION = "Context ID"
 
 type ControllerContext struct {
-	router             *router.Router
+	router             *SecureRouter
 	managerContext     *manager.ManagerContext
 	managerSessionData *session.ManagerSessionData
 }
 
 func NewControllerContext(
-	router *router.Router,
+	router *SecureRouter,
 	managerContext *manager.ManagerContext,
 	managerSessionData *session.ManagerSessionData,
 ) ControllerContext {
@@ -32,11 +33,11 @@ func NewControllerContext(
 	}
 
 	instance.router.
-		RouteDocument(http.MethodPost, instance.importItem, "import/context", instance.docImportItem()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_WRITE, instance.importItem, "import/context", instance.docImportItem()).
 		//
-		RouteDocument(http.MethodGet, instance.findFromUser, "context", instance.docFindFromUser()).
-		RouteDocument(http.MethodPut, instance.update, "context", instance.docUpdate()).
-		RouteDocument(http.MethodGet, instance.find, "context/{%s}", instance.docFind())
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.findFromUser, "context", instance.docFindFromUser()).
+		RouteDocument(http.MethodPut, role.PERMISSION_COLLECTIONS_WRITE, instance.update, "context", instance.docUpdate()).
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.find, "context/{%s}", instance.docFind())
 
 	return instance
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/Rafael24595/go-api-core/src/domain/context"
 	"github.com/Rafael24595/go-api-core/src/domain/formatter/curl"
 	"github.com/Rafael24595/go-api-core/src/domain/mock"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
@@ -24,7 +25,7 @@ const SW_RAW_DESCRIPTION = "Raw flag"
 const CURL_COMMAND_DESCRIPTION = "CURL commands"
 
 type ControllerCurl struct {
-	router             *router.Router
+	router             *SecureRouter
 	managerRequest     *manager.ManagerRequest
 	managerCollection  *manager.ManagerCollection
 	managerGroup       *manager.ManagerGroup
@@ -34,7 +35,7 @@ type ControllerCurl struct {
 }
 
 func NewControllerCurl(
-	router *router.Router,
+	router *SecureRouter,
 	managerRequest *manager.ManagerRequest,
 	managerCollection *manager.ManagerCollection,
 	managerGroup *manager.ManagerGroup,
@@ -53,9 +54,9 @@ func NewControllerCurl(
 	}
 
 	router.
-		RouteDocument(http.MethodGet, instance.encodeCurl, "curl/request/{%s}", instance.docEncodeCurl()).
-		RouteDocument(http.MethodPost, instance.decodeCurl, "curl/request", instance.docDecodeCurl()).
-		RouteDocument(http.MethodGet, instance.encodeEndPoint, "curl/endpoint/{%s}", instance.docEncodeEndPoint())
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.encodeCurl, "curl/request/{%s}", instance.docEncodeCurl()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_READ, instance.decodeCurl, "curl/request", instance.docDecodeCurl()).
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.encodeEndPoint, "curl/endpoint/{%s}", instance.docEncodeEndPoint())
 
 	return instance
 }
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
@@ -18,17 +19,17 @@ const STATUS_CODE = "status"
 const STATUS_CODE_DESCRIPTION = "HTTP status code"
 
 type ControllerDev struct {
-	router *router.Router
+	router *SecureRouter
 }
 
-func NewControllerDev(router *router.Router) ControllerDev {
+func NewControllerDev(router *SecureRouter) ControllerDev {
 	instance := ControllerDev{
 		router: router,
 	}
 
 	router.
-		RouteDocument(http.MethodGet, instance.playground, "dev/playground", instance.doPlayground()).
-		RouteDocument(http.MethodPost, instance.paylaod, "dev/print/payload", instance.doPayload())
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.playground, "dev/playground", instance.doPlayground()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.paylaod, "dev/print/payload", instance.doPayload())
 
 	return instance
 }
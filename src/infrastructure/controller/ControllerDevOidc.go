@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Rafael24595/go-api-render/src/commons/oidc"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+const devOidcPath = "dev/oidc"
+
+type ControllerDevOidc struct {
+	router  *SecureRouter
+	standIn *oidc.StandIn
+}
+
+func NewControllerDevOidc(router *SecureRouter, standIn *oidc.StandIn) ControllerDevOidc {
+	instance := ControllerDevOidc{
+		router:  router,
+		standIn: standIn,
+	}
+
+	router.
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.discovery, "dev/oidc/.well-known/openid-configuration", instance.docDiscovery()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.jwks, "dev/oidc/jwks", instance.docJwks()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.prompt, "dev/oidc/authorize", instance.docPrompt()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.authorize, "dev/oidc/authorize", instance.docAuthorize()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.token, "dev/oidc/token", instance.docToken())
+
+	return instance
+}
+
+func (c *ControllerDevOidc) docDiscovery() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Discovery document of the local stand-in identity provider.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[oidc.Discovery](),
+		},
+		Tags: docs.DocTags("dev", "oidc"),
+	}
+}
+
+func (c *ControllerDevOidc) discovery(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return result.JsonOk(c.standIn.Discovery(devIssuer(r)))
+}
+
+func (c *ControllerDevOidc) docJwks() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Public keys of the local stand-in identity provider.",
+		Tags:        docs.DocTags("dev", "oidc"),
+	}
+}
+
+func (c *ControllerDevOidc) jwks(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return result.JsonOk(c.standIn.Jwks())
+}
+
+func (c *ControllerDevOidc) docPrompt() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Shows a form to choose the user and groups the stand-in identity provider will assert.",
+		Tags:        docs.DocTags("dev", "oidc"),
+	}
+}
+
+func (c *ControllerDevOidc) prompt(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	fields := strings.Builder{}
+	for key, values := range r.URL.Query() {
+		for _, value := range values {
+			fields.WriteString(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+				html.EscapeString(key), html.EscapeString(value)))
+		}
+	}
+
+	page := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><title>Stand-in identity provider</title></head>
+<body>
+<form method="post">
+%s
+<label>Username <input name="username" required></label>
+<label>Groups <input name="groups" placeholder="comma separated"></label>
+<button type="submit">Sign in</button>
+</form>
+</body>
+</html>`, fields.String())
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(page))
+
+	return result.Continue()
+}
+
+func (c *ControllerDevOidc) docAuthorize() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Approves the authorization request and redirects back to the client with a code.",
+		Responses: docs.DocResponses{
+			"302": docs.DocText(),
+		},
+		Tags: docs.DocTags("dev", "oidc"),
+	}
+}
+
+func (c *ControllerDevOidc) authorize(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	if err := r.ParseForm(); err != nil {
+		return result.Err(http.StatusBadRequest, err)
+	}
+
+	groups := make([]string, 0)
+	for _, v := range strings.Split(r.PostForm.Get("groups"), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			groups = append(groups, v)
+		}
+	}
+
+	location, err := c.standIn.Authorize(r.PostForm, strings.TrimSpace(r.PostForm.Get("username")), groups)
+	if err != nil {
+		return result.Err(http.StatusBadRequest, err)
+	}
+
+	http.Redirect(w, r, location, http.StatusSeeOther)
+	return result.Continue()
+}
+
+func (c *ControllerDevOidc) docToken() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Redeems an authorization code for an ID token signed by the stand-in identity provider.",
+		Tags:        docs.DocTags("dev", "oidc"),
+	}
+}
+
+func (c *ControllerDevOidc) token(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	if err := r.ParseForm(); err != nil {
+		return result.Err(http.StatusBadRequest, err)
+	}
+
+	clientId := ""
+	if user, _, ok := r.BasicAuth(); ok {
+		clientId, _ = url.QueryUnescape(user)
+	}
+
+	response, err := c.standIn.Token(devIssuer(r), clientId, r.PostForm)
+	if err != nil {
+		return result.Err(http.StatusBadRequest, err)
+	}
+
+	return result.JsonOk(response)
+}
+
+func devIssuer(r *http.Request) string {
+	protocol := "http"
+	if r.TLS != nil {
+		protocol = "https"
+	}
+	return fmt.Sprintf("%s://%s%s%s", protocol, r.Host, BASE_PATH, devOidcPath)
+}
@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Rafael24595/go-api-render/src/application/manager"
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/commons/totp"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+type ControllerFactor struct {
+	router         *SecureRouter
+	managerFactor  *manager.ManagerFactor
+	managerAccount *manager.ManagerAccount
+}
+
+func NewControllerFactor(
+	router *SecureRouter,
+	managerFactor *manager.ManagerFactor,
+	managerAccount *manager.ManagerAccount,
+) ControllerFactor {
+	instance := ControllerFactor{
+		router:         router,
+		managerFactor:  managerFactor,
+		managerAccount: managerAccount,
+	}
+
+	router.
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.find, "user/2fa", instance.docFind()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.enroll, "user/2fa", instance.docEnroll()).
+		RouteDocument(http.MethodPut, role.PERMISSION_NONE, instance.confirm, "user/2fa", instance.docConfirm()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_NONE, instance.disable, "user/2fa", instance.docDisable()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.regenerate, "user/2fa/recovery", instance.docRegenerate()).
+		//
+		RouteDocument(http.MethodGet, role.PERMISSION_USERS_MANAGE, instance.findPolicy, "admin/2fa", instance.docFindPolicy()).
+		RouteDocument(http.MethodPut, role.PERMISSION_USERS_MANAGE, instance.resolvePolicy, "admin/2fa", instance.docResolvePolicy()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_USERS_MANAGE, instance.reset, "admin/users/{%s}/2fa", instance.docReset())
+
+	return instance
+}
+
+func (c *ControllerFactor) docFind() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Gets the two-factor authentication status of the authenticated user.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseFactor](),
+		},
+	}
+}
+
+func (c *ControllerFactor) find(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	session, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	response := responseFactor{
+		Required: c.managerFactor.IsRequired(session),
+	}
+
+	if factor, ok := c.managerFactor.Find(session.Username); ok {
+		response.Enabled = factor.IsEnabled()
+		response.Pending = !factor.IsEnabled()
+		response.Recovery = len(factor.Recovery)
+		response.Since = factor.Enabled
+	}
+
+	return result.JsonOk(response)
+}
+
+func (c *ControllerFactor) docEnroll() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Starts the two-factor enrollment. Returns the secret and the otpauth URI to render as a QR code.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseFactorEnroll](),
+			"409": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerFactor) enroll(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	session, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	factor, err := c.managerFactor.Enroll(session.Username)
+	if err != nil {
+		return result.Err(http.StatusConflict, err)
+	}
+
+	issuer := configuration.Instance().TotpIssuer()
+
+	return result.JsonOk(responseFactorEnroll{
+		Secret: factor.Secret,
+		Uri:    totp.URI(issuer, session.Username, factor.Secret),
+	})
+}
+
+func (c *ControllerFactor) docConfirm() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Confirms the two-factor enrollment with a code from the authenticator. Returns the recovery codes only once.",
+		Request:     docs.DocJsonPayload[requestFactorCode](),
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseFactorRecovery](),
+			"422": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerFactor) confirm(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	session, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	request, res := router.InputJson[requestFactorCode](r)
+	if res != nil {
+		return *res
+	}
+
+	codes, err := c.managerFactor.Confirm(session.Username, request.Code)
+	if err != nil {
+		return result.Err(http.StatusUnprocessableEntity, err)
+	}
+
+	return result.JsonOk(responseFactorRecovery{
+		Codes: codes,
+	})
+}
+
+func (c *ControllerFactor) docDisable() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Disables the two-factor authentication with a current or recovery code.",
+		Request:     docs.DocJsonPayload[requestFactorCode](),
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseFactor](),
+			"403": docs.DocText(),
+			"422": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerFactor) disable(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	session, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	if c.managerFactor.IsRequired(session) {
+		return result.TextErr(http.StatusForbidden, "two-factor authentication is required for this user")
+	}
+
+	request, res := router.InputJson[requestFactorCode](r)
+	if res != nil {
+		return *res
+	}
+
+	if err := c.managerFactor.Disable(session.Username, request.Code); err != nil {
+		return result.Err(http.StatusUnprocessableEntity, err)
+	}
+
+	return c.find(w, r, ctx)
+}
+
+func (c *ControllerFactor) docRegenerate() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Replaces the recovery codes with a new set after verifying a current or recovery code.",
+		Request:     docs.DocJsonPayload[requestFactorCode](),
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseFactorRecovery](),
+			"422": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerFactor) regenerate(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	session, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	request, res := router.InputJson[requestFactorCode](r)
+	if res != nil {
+		return *res
+	}
+
+	codes, err := c.managerFactor.Regenerate(session.Username, request.Code)
+	if err != nil {
+		return result.Err(http.StatusUnprocessableEntity, err)
+	}
+
+	return result.JsonOk(responseFactorRecovery{
+		Codes: codes,
+	})
+}
+
+func (c *ControllerFactor) docFindPolicy() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Gets whether two-factor authentication is required for admin users.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseFactorPolicy](),
+		},
+	}
+}
+
+func (c *ControllerFactor) findPolicy(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return result.JsonOk(responseFactorPolicy{
+		Admins: c.managerFactor.AdminsRequired(),
+	})
+}
+
+func (c *ControllerFactor) docResolvePolicy() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Sets whether two-factor authentication is required for admin users.",
+		Request:     docs.DocJsonPayload[requestFactorPolicy](),
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseFactorPolicy](),
+			"409": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerFactor) resolvePolicy(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	admin, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	request, res := router.InputJson[requestFactorPolicy](r)
+	if res != nil {
+		return *res
+	}
+
+	if request.Admins && !c.managerFactor.IsEnabled(admin.Username) {
+		return result.TextErr(http.StatusConflict, "enable two-factor authentication on your account before requiring it")
+	}
+
+	return result.JsonOk(responseFactorPolicy{
+		Admins: c.managerFactor.RequireAdmins(admin.Username, request.Admins),
+	})
+}
+
+func (c *ControllerFactor) docReset() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Removes the two-factor authentication of a user that lost access to it.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"202": docs.DocText(),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerFactor) reset(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	if !c.managerFactor.Reset(r.PathValue(USERNAME)) {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	return result.Accept(http.StatusAccepted)
+}
@@ -5,23 +5,24 @@ import (
 	"os"
 	"strings"
 
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
 )
 
 type ControllerFront struct {
-	router *router.Router
+	router *SecureRouter
 }
 
 func NewControllerFront(
-	router *router.Router) ControllerFront {
+	router *SecureRouter) ControllerFront {
 	instance := ControllerFront{
 		router: router,
 	}
 
 	router.
-		RouteDocument(http.MethodGet, instance.client, "/", instance.docClient())
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.client, "/", instance.docClient())
 
 	return instance
 }
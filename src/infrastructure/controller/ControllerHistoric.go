@@ -7,20 +7,21 @@ import (
 	"github.com/Rafael24595/go-api-core/src/application/session"
 	action_domain "github.com/Rafael24595/go-api-core/src/domain/action"
 	"github.com/Rafael24595/go-api-core/src/infrastructure/dto"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
 )
 
 type ControllerHistoric struct {
-	router             *router.Router
+	router             *SecureRouter
 	managerRequest     *manager.ManagerRequest
 	managerHistoric    *manager.ManagerHistoric
 	managerSessionData *session.ManagerSessionData
 }
 
 func NewControllerHistoric(
-	router *router.Router,
+	router *SecureRouter,
 	managerRequest *manager.ManagerRequest,
 	managerHistoric *manager.ManagerHistoric,
 	managerSessionData *session.ManagerSessionData,
@@ -33,9 +34,9 @@ func NewControllerHistoric(
 	}
 
 	router.
-		RouteDocument(http.MethodGet, instance.find, "historic", instance.docFind()).
-		RouteDocument(http.MethodPost, instance.insert, "historic", instance.docInsert()).
-		RouteDocument(http.MethodDelete, instance.delete, "historic/{%s}", instance.docDelete())
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.find, "historic", instance.docFind()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_WRITE, instance.insert, "historic", instance.docInsert()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_COLLECTIONS_WRITE, instance.delete, "historic/{%s}", instance.docDelete())
 
 	return instance
 }
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Rafael24595/go-api-render/src/application/manager"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+const ADDRESS = "address"
+const ADDRESS_DESCRIPTION = "Client address"
+
+type ControllerLockout struct {
+	router         *SecureRouter
+	managerLockout *manager.ManagerLockout
+}
+
+func NewControllerLockout(
+	router *SecureRouter,
+	managerLockout *manager.ManagerLockout,
+) ControllerLockout {
+	instance := ControllerLockout{
+		router:         router,
+		managerLockout: managerLockout,
+	}
+
+	router.
+		RouteDocument(http.MethodGet, role.PERMISSION_USERS_MANAGE, instance.findAll, "admin/lockouts", instance.docFindAll()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_USERS_MANAGE, instance.clearAll, "admin/lockouts", instance.docClearAll()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_USERS_MANAGE, instance.clearUser, "admin/lockouts/users/{%s}", instance.docClearUser()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_USERS_MANAGE, instance.clearAddress, "admin/lockouts/addresses/{%s}", instance.docClearAddress())
+
+	return instance
+}
+
+func (c *ControllerLockout) docFindAll() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Lists the users and client addresses with failed logins or locked out.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]manager.Lockout](),
+		},
+	}
+}
+
+func (c *ControllerLockout) findAll(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return result.JsonOk(c.managerLockout.FindAll())
+}
+
+func (c *ControllerLockout) docClearAll() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Clears every lockout.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]manager.Lockout](),
+		},
+	}
+}
+
+func (c *ControllerLockout) clearAll(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	c.managerLockout.ClearAll()
+
+	return result.JsonOk(c.managerLockout.FindAll())
+}
+
+func (c *ControllerLockout) docClearUser() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Clears the lockout of a user.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]manager.Lockout](),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerLockout) clearUser(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return c.clear(ctx, manager.LOCKOUT_USER, r.PathValue(USERNAME))
+}
+
+func (c *ControllerLockout) docClearAddress() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Clears the lockout of a client address.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(ADDRESS, ADDRESS_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]manager.Lockout](),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerLockout) clearAddress(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return c.clear(ctx, manager.LOCKOUT_ADDRESS, r.PathValue(ADDRESS))
+}
+
+func (c *ControllerLockout) clear(ctx *router.Context, kind manager.LockoutKind, subject string) result.Result {
+	if !c.managerLockout.Clear(kind, subject) {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	return result.JsonOk(c.managerLockout.FindAll())
+}
@@ -1,10 +1,11 @@
 package controller
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/Rafael24595/go-api-core/src/application/session"
 	"github.com/Rafael24595/go-api-core/src/domain/action"
@@ -12,7 +13,13 @@ import (
 	"github.com/Rafael24595/go-api-render/src/application/manager"
 	auth "github.com/Rafael24595/go-api-render/src/commons/auth/Jwt.go"
 	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/domain/account"
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+	"github.com/Rafael24595/go-api-render/src/domain/device"
+	"github.com/Rafael24595/go-api-render/src/domain/revision"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-api-render/src/domain/web"
+	"github.com/Rafael24595/go-log/log"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
@@ -24,42 +31,87 @@ const AUTH_COOKIE_DESCRIPTION = "Session cookie token"
 const REFRESH_COOKIE = "go_api_refresh"
 const REFRESH_COOKIE_DESCRIPTION = "User refresh token"
 
+const REFRESH_PATH = BASE_PATH + "refresh"
+
+const WEB_KEY = "key"
+const WEB_KEY_DESCRIPTION = "Web data key"
+
+const WEB_REVISION = "rev"
+const WEB_REVISION_DESCRIPTION = "Web data revision number"
+
 type ControllerLogin struct {
-	router     *router.Router
-	managerWeb *manager.ManagerWeb
+	router          *SecureRouter
+	managerWeb      *manager.ManagerWeb
+	managerDevice   *manager.ManagerDevice
+	managerFactor   *manager.ManagerFactor
+	managerLockout  *manager.ManagerLockout
+	managerAccount  *manager.ManagerAccount
+	managerAudit    *manager.ManagerAudit
+	managerPassword *manager.ManagerPassword
+	managerDeletion *manager.ManagerDeletion
 }
 
 func NewControllerLogin(
-	router *router.Router,
+	router *SecureRouter,
 	managerWeb *manager.ManagerWeb,
+	managerDevice *manager.ManagerDevice,
+	managerFactor *manager.ManagerFactor,
+	managerLockout *manager.ManagerLockout,
+	managerAccount *manager.ManagerAccount,
+	managerAudit *manager.ManagerAudit,
+	managerPassword *manager.ManagerPassword,
+	managerDeletion *manager.ManagerDeletion,
 ) ControllerLogin {
 	instance := ControllerLogin{
-		router:     router,
-		managerWeb: managerWeb,
+		router:          router,
+		managerWeb:      managerWeb,
+		managerDevice:   managerDevice,
+		managerFactor:   managerFactor,
+		managerLockout:  managerLockout,
+		managerAccount:  managerAccount,
+		managerAudit:    managerAudit,
+		managerPassword: managerPassword,
+		managerDeletion: managerDeletion,
 	}
 
 	router.
-		RouteDocument(http.MethodPost, instance.login, "login", instance.docLogin()).
-		RouteDocument(http.MethodDelete, instance.logout, "login", instance.docLogout()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.login, "login", instance.docLogin()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_NONE, instance.logout, "login", instance.docLogout()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.challenge, "login/2fa", instance.docChallenge()).
 		//
-		RouteDocument(http.MethodGet, instance.refresh, "refresh", instance.docRefresh()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.refresh, "refresh", instance.docRefresh()).
 		//
-		RouteDocument(http.MethodGet, instance.user, "user", instance.docUser()).
-		RouteDocument(http.MethodPost, instance.signin, "user", instance.docSignin()).
-		RouteDocument(http.MethodPut, instance.verify, "user", instance.docVerify()).
-		RouteDocument(http.MethodDelete, instance.delete, "user", instance.docDelete()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.user, "user", instance.docUser()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.signin, "user", instance.docSignin()).
+		RouteDocument(http.MethodPut, role.PERMISSION_NONE, instance.verify, "user", instance.docVerify()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_NONE, instance.delete, "user", instance.docDelete()).
 		//
-		RouteDocument(http.MethodGet, instance.findWebData, "user/web", instance.docFindWebData()).
-		RouteDocument(http.MethodPost, instance.resolveWebData, "user/web", instance.docResolveWebData())
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.findWebData, "user/web", instance.docFindWebData()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.resolveWebData, "user/web", instance.docResolveWebData()).
+		RouteDocument(http.MethodPatch, role.PERMISSION_NONE, instance.patchWebData, "user/web", instance.docPatchWebData()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.findWebHistory, "user/web/history", instance.docFindWebHistory()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.restoreWebRevision, "user/web/history/{%s}/restore", instance.docRestoreWebRevision()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.findWebDataKey, "user/web/{%s}", instance.docFindWebDataKey()).
+		RouteDocument(http.MethodPut, role.PERMISSION_NONE, instance.resolveWebDataKey, "user/web/{%s}", instance.docResolveWebDataKey()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_NONE, instance.deleteWebDataKey, "user/web/{%s}", instance.docDeleteWebDataKey()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.findWebNamespace, "user/web/namespaces/{%s}", instance.docFindWebNamespace()).
+		RouteDocument(http.MethodPut, role.PERMISSION_NONE, instance.resolveWebNamespace, "user/web/namespaces/{%s}", instance.docResolveWebNamespace()).
+		RouteDocument(http.MethodPatch, role.PERMISSION_NONE, instance.patchWebNamespace, "user/web/namespaces/{%s}", instance.docPatchWebNamespace()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_NONE, instance.deleteWebNamespace, "user/web/namespaces/{%s}", instance.docDeleteWebNamespace())
 
 	return instance
 }
 
 func (c *ControllerLogin) docLogin() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Authenticate user and establish a session with JWT and refresh token cookies.",
+		Description: "Authenticate user and establish a session with JWT and refresh token cookies. If the user has two-factor authentication enabled, a challenge to answer in login/2fa is returned instead. Sessions opened with remember_me follow the longer remember policy and keep the refresh cookie across browser restarts.",
 		Request:     docs.DocJsonPayload[requestLogin](),
-		Responses:   c.docUser().Responses,
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseUserData](),
+			"202": docs.DocJsonPayload[responseLoginChallenge](),
+			"403": docs.DocText(),
+			"429": docs.DocText(),
+		},
 	}
 }
 
@@ -69,9 +121,18 @@ func (c *ControllerLogin) login(w http.ResponseWriter, r *http.Request, ctx *rou
 		return *res
 	}
 
+	address := clientAddress(r)
+	if remaining := c.managerLockout.Check(login.Username, address); remaining > 0 {
+		return retryAfter(w, remaining)
+	}
+
 	sessions := session.InstanceManagerSession()
 	session, err := sessions.Authorize(login.Username, login.Password)
 	if err != nil {
+		recordEvent(c.managerAudit, r, audit.KIND_LOGIN_FAILED, login.Username, login.Username, err.Error())
+		if remaining := c.managerLockout.Fail(login.Username, address); remaining > 0 {
+			return retryAfter(w, remaining)
+		}
 		return result.Err(http.StatusUnauthorized, err)
 	}
 
@@ -79,18 +140,92 @@ func (c *ControllerLogin) login(w http.ResponseWriter, r *http.Request, ctx *rou
 		return result.Reject(http.StatusUnprocessableEntity)
 	}
 
-	_, _, err = defineSession(w, session)
-	if err != nil {
+	c.managerLockout.Succeed(session.Username)
+
+	if c.managerAccount.IsDisabled(session.Username) {
+		return result.TextErr(http.StatusForbidden, "the account is disabled")
+	}
+
+	c.managerAccount.Register(session.Username, account.PROVIDER_LOCAL)
+
+	if c.managerFactor.IsEnabled(session.Username) {
+		id, expires, err := c.managerFactor.Challenge(session.Username)
+		if err != nil {
+			return result.Err(http.StatusInternalServerError, err)
+		}
+
+		return jsonStatus(w, http.StatusAccepted, responseLoginChallenge{
+			Challenge: id,
+			Method:    "totp",
+			Expires:   expires.UnixMilli(),
+		})
+	}
+
+	if err := openSession(w, r, c.managerDevice, session, login.RememberMe); err != nil {
 		return result.Err(http.StatusUnauthorized, err)
 	}
 
-	sessions.Visited(session)
+	recordEvent(c.managerAudit, r, audit.KIND_LOGIN, session.Username, session.Username, "password")
 
 	ctx.Put(USER, login.Username)
 
 	return c.user(w, r, ctx)
 }
 
+func (c *ControllerLogin) docChallenge() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Completes a two-factor login answering the challenge with a code from the authenticator or a recovery code. The remember_me flag of the login must be sent again.",
+		Request:     docs.DocJsonPayload[requestLoginChallenge](),
+		Responses:   c.docUser().Responses,
+	}
+}
+
+func (c *ControllerLogin) challenge(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	request, res := router.InputJson[requestLoginChallenge](r)
+	if res != nil {
+		return *res
+	}
+
+	owner, _ := c.managerFactor.Owner(request.Challenge)
+
+	address := clientAddress(r)
+	if remaining := c.managerLockout.Check(owner, address); remaining > 0 {
+		return retryAfter(w, remaining)
+	}
+
+	username, err := c.managerFactor.Answer(request.Challenge, request.Code)
+	if err != nil {
+		recordEvent(c.managerAudit, r, audit.KIND_LOGIN_FAILED, owner, owner, err.Error())
+		if remaining := c.managerLockout.Fail(owner, address); remaining > 0 {
+			return retryAfter(w, remaining)
+		}
+		return result.Err(http.StatusUnauthorized, err)
+	}
+
+	c.managerLockout.Succeed(username)
+
+	sessions := session.InstanceManagerSession()
+	session, exists := sessions.Find(username)
+	if !exists {
+		err = errors.New("user not exists")
+		return result.Err(http.StatusNotFound, err)
+	}
+
+	if c.managerAccount.IsDisabled(username) {
+		return result.TextErr(http.StatusForbidden, "the account is disabled")
+	}
+
+	if err := openSession(w, r, c.managerDevice, session, request.RememberMe); err != nil {
+		return result.Err(http.StatusUnauthorized, err)
+	}
+
+	recordEvent(c.managerAudit, r, audit.KIND_LOGIN, username, username, "2fa")
+
+	ctx.Put(USER, username)
+
+	return c.user(w, r, ctx)
+}
+
 func (c *ControllerLogin) docLogout() docs.DocRoute {
 	return docs.DocRoute{
 		Description: "Logout the current user and invalidate session cookies.",
@@ -99,6 +234,9 @@ func (c *ControllerLogin) docLogout() docs.DocRoute {
 }
 
 func (c *ControllerLogin) logout(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	if username, ok := c.closeDevice(r); ok {
+		recordEvent(c.managerAudit, r, audit.KIND_LOGOUT, username, username, "")
+	}
 	eraseSession(w)
 	ctx.Put(USER, action.ANONYMOUS_OWNER)
 	return c.user(w, r, ctx)
@@ -117,14 +255,14 @@ func (c *ControllerLogin) docRefresh() docs.DocRoute {
 }
 
 func (c *ControllerLogin) refresh(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
-	cookie, err := r.Cookie(REFRESH_COOKIE)
+	cookie, err := readCookie(r, REFRESH_COOKIE, REFRESH_PATH)
 	if err != nil {
 		return result.Err(http.StatusUnauthorized, err)
 	}
 
-	claims, err := auth.ValidateJWT(cookie.Value)
+	claims, err := auth.ValidateJWT(cookie.Value, auth.TOKEN_REFRESH)
 	if err != nil {
-		if claims != nil && 0 >= time.Until(claims.ExpiresAt.Time) {
+		if auth.IsExpired(err) {
 			eraseSession(w)
 		}
 		return result.Err(http.StatusUnauthorized, err)
@@ -139,11 +277,33 @@ func (c *ControllerLogin) refresh(w http.ResponseWriter, r *http.Request, ctx *r
 		return result.Err(http.StatusNotFound, err)
 	}
 
-	if cookie.Value != session.Refresh {
+	if c.managerAccount.IsDisabled(user) {
+		eraseSession(w)
+		return result.TextErr(http.StatusForbidden, "the account is disabled")
+	}
+
+	device, ok := c.managerDevice.Find(user, claims.Session)
+	if !ok || device.IsRevoked() {
+		eraseSession(w)
 		return result.Reject(http.StatusUnauthorized)
 	}
 
-	_, _, err = defineSession(w, session)
+	if c.managerDevice.IsExpired(device) {
+		c.managerDevice.Revoke(user, device)
+		eraseSession(w)
+		return result.TextErr(http.StatusUnauthorized, "the session has expired")
+	}
+
+	if claims.ID != device.Refresh {
+		c.managerDevice.Revoke(user, device)
+		eraseSession(w)
+		log.Warningf("Refresh token reuse detected for user %q, the session %q has been revoked", user, device.Id)
+		return result.TextErr(http.StatusUnauthorized, "the refresh token has already been used")
+	}
+
+	device = c.managerDevice.Rotate(user, device, r.UserAgent(), clientAddress(r))
+
+	_, _, err = defineSession(w, c.managerDevice, session, device)
 	if err != nil {
 		return result.Err(http.StatusBadRequest, err)
 	}
@@ -177,7 +337,8 @@ func (c *ControllerLogin) user(w http.ResponseWriter, r *http.Request, ctx *rout
 		Username:  user.Username,
 		Timestamp: user.Timestamp,
 		FirstTime: user.Count < 0,
-		Roles:     user.Roles,
+		Expired:   c.managerPassword.IsExpired(user.Username),
+		Roles:     c.managerAccount.Roles(user.Username, user.Roles),
 	}
 
 	return result.JsonOk(response)
@@ -185,10 +346,11 @@ func (c *ControllerLogin) user(w http.ResponseWriter, r *http.Request, ctx *rout
 
 func (c *ControllerLogin) docSignin() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Register a new user using the current user's session context.",
+		Description: "Register a new user using the current user's session context. A password breaking the password policy is rejected with the rules it breaks.",
 		Request:     docs.DocJsonPayload[requestSigninUser](),
 		Responses: docs.DocResponses{
 			"200": docs.DocJsonPayload[responseUserData](),
+			"422": docs.DocJsonPayload[responseFieldErrors](),
 		},
 	}
 }
@@ -209,6 +371,10 @@ func (c *ControllerLogin) signin(w http.ResponseWriter, r *http.Request, ctx *ro
 		return result.Err(http.StatusNotFound, err)
 	}
 
+	if violations := c.managerPassword.Check(request.Username, request.Password1, ""); len(violations) > 0 {
+		return jsonStatus(w, http.StatusUnprocessableEntity, makeResponseFieldErrors("password_1", violations))
+	}
+
 	roles := make([]domain_session.Role, 0)
 	if request.IsAdmin {
 		roles = append(roles, domain_session.ROLE_ADMIN)
@@ -219,6 +385,11 @@ func (c *ControllerLogin) signin(w http.ResponseWriter, r *http.Request, ctx *ro
 		return result.Err(http.StatusUnprocessableEntity, err)
 	}
 
+	c.managerAccount.Register(session.Username, account.PROVIDER_LOCAL)
+	c.managerPassword.Changed(session.Username, request.Password1)
+
+	recordEvent(c.managerAudit, r, audit.KIND_USER_CREATE, username, session.Username, account.PROVIDER_LOCAL)
+
 	ctx.Put(USER, session.Username)
 
 	return c.user(w, r, ctx)
@@ -226,10 +397,12 @@ func (c *ControllerLogin) signin(w http.ResponseWriter, r *http.Request, ctx *ro
 
 func (c *ControllerLogin) docVerify() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Change the user's password by verifying the old one and setting a new one.",
+		Description: "Change the user's password by verifying the old one and setting a new one. Once the old password is verified, a new password breaking the password policy is rejected with the rules it breaks.",
 		Request:     docs.DocJsonPayload[requestVerify](),
 		Responses: docs.DocResponses{
 			"200": docs.DocJsonPayload[responseUserData](),
+			"422": docs.DocJsonPayload[responseFieldErrors](),
+			"429": docs.DocText(),
 		},
 	}
 }
@@ -242,7 +415,26 @@ func (c *ControllerLogin) verify(w http.ResponseWriter, r *http.Request, ctx *ro
 		return *res
 	}
 
+	address := clientAddress(r)
+	if remaining := c.managerLockout.Check(username, address); remaining > 0 {
+		return retryAfter(w, remaining)
+	}
+
 	sessions := session.InstanceManagerSession()
+	if _, err := sessions.Authorize(username, verify.OldPassword); err != nil {
+		recordEvent(c.managerAudit, r, audit.KIND_LOGIN_FAILED, username, username, err.Error())
+		if remaining := c.managerLockout.Fail(username, address); remaining > 0 {
+			return retryAfter(w, remaining)
+		}
+		return result.Err(http.StatusUnauthorized, err)
+	}
+
+	c.managerLockout.Succeed(username)
+
+	if violations := c.managerPassword.Check(username, verify.NewPassword1, verify.OldPassword); len(violations) > 0 {
+		return jsonStatus(w, http.StatusUnprocessableEntity, makeResponseFieldErrors("new_password_1", violations))
+	}
+
 	session, err := sessions.Verify(username, verify.OldPassword, verify.NewPassword1, verify.NewPassword2)
 	if err != nil {
 		return result.Err(http.StatusUnauthorized, err)
@@ -252,7 +444,20 @@ func (c *ControllerLogin) verify(w http.ResponseWriter, r *http.Request, ctx *ro
 		return result.Reject(http.StatusInternalServerError)
 	}
 
-	_, _, err = defineSession(w, session)
+	device, ok := c.managerDevice.Find(username, findSessionId(ctx))
+	if !ok || device.IsRevoked() {
+		device = c.managerDevice.Open(username, r.UserAgent(), clientAddress(r), false)
+	} else {
+		device = c.managerDevice.Rotate(username, device, r.UserAgent(), clientAddress(r))
+	}
+
+	c.managerDevice.RevokeAll(username, device.Id)
+
+	c.managerPassword.Changed(username, verify.NewPassword1)
+
+	recordEvent(c.managerAudit, r, audit.KIND_PASSWORD_CHANGE, username, username, "")
+
+	_, _, err = defineSession(w, c.managerDevice, session, device)
 	if err != nil {
 		return result.Err(401, err)
 	}
@@ -264,9 +469,13 @@ func (c *ControllerLogin) verify(w http.ResponseWriter, r *http.Request, ctx *ro
 
 func (c *ControllerLogin) docDelete() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Delete the current user account and clear session data.",
+		Description: "Delete the current user account with everything it owns and clear session data. With a deletion grace period configured, the account is disabled and its data purged once the period is over.",
 		Responses: docs.DocResponses{
-			"200": docs.DocJsonPayload[responseUserData](),
+			"200": docs.DocJsonPayload[manager.DeletionReport](),
+			"202": docs.DocJsonPayload[manager.DeletionReport](),
+			"500": docs.DocJsonPayload[manager.DeletionIncomplete](),
+			"403": docs.DocText(),
+			"404": docs.DocText(),
 		},
 	}
 }
@@ -282,40 +491,46 @@ func (c *ControllerLogin) delete(w http.ResponseWriter, r *http.Request, ctx *ro
 		return result.Err(http.StatusNotFound, err)
 	}
 
-	if _, err := sessions.Delete(user, user); err != nil {
+	report, err := c.managerDeletion.Delete(user, user)
+	incomplete, isIncomplete := err.(*manager.DeletionIncomplete)
+	if err != nil && !isIncomplete {
 		return result.Err(http.StatusForbidden, err)
 	}
 
-	c.managerWeb.Delete(username)
+	recordEvent(c.managerAudit, r, audit.KIND_USER_DELETE, username, username, deletionDetail(report, incomplete))
 
 	eraseSession(w)
 
 	ctx.Put(USER, action.ANONYMOUS_OWNER)
 
-	return c.user(w, r, ctx)
+	return deletionResult(w, report, incomplete)
 }
 
 func (c *ControllerLogin) docFindWebData() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Get the currently authenticated user's web data.",
+		Description: "Get the currently authenticated user's web data. The ETag header identifies its version.",
 		Responses: docs.DocResponses{
 			"200": docs.DocJsonPayload[web.WebData](),
 		},
 	}
 }
 
-func (c *ControllerLogin) findWebData(_ http.ResponseWriter, _ *http.Request, ctx *router.Context) result.Result {
+func (c *ControllerLogin) findWebData(w http.ResponseWriter, _ *http.Request, ctx *router.Context) result.Result {
 	username := findUser(ctx)
 	webData, _ := c.managerWeb.FindByOwner(username)
+	writeETag(w, webData.ETag())
 	return result.JsonOk(webData)
 }
 
 func (c *ControllerLogin) docResolveWebData() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Updates the currently authenticated user's web data.",
+		Description: "Updates the currently authenticated user's web data. Every namespace that changes must satisfy its schema and quota. When the If-Match header is sent, the data is only replaced if it still matches the given ETag; otherwise the current data is returned so the changes can be merged.",
 		Request:     docs.DocJsonPayload[web.WebData](),
 		Responses: docs.DocResponses{
 			"200": docs.DocJsonPayload[web.WebData](),
+			"409": docs.DocJsonPayload[web.WebData](),
+			"413": docs.DocText(),
+			"422": docs.DocJsonPayload[responseFieldErrors](),
 		},
 	}
 }
@@ -333,73 +548,440 @@ func (c *ControllerLogin) resolveWebData(w http.ResponseWriter, r *http.Request,
 		return *res
 	}
 
-	output := c.managerWeb.Resolve(username, &input)
-	return result.JsonOk(output)
+	output, ok, err := c.managerWeb.ResolveIf(username, &input, matchesWebData(r))
+
+	return webDataResult(w, output, ok, err)
 }
 
-func defineSession(w http.ResponseWriter, sess *domain_session.Session) (string, string, error) {
-	sessions := session.InstanceManagerSession()
+func (c *ControllerLogin) docPatchWebData() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Applies a JSON Merge Patch to the currently authenticated user's web data. Only the data member can be patched: a null entry removes the key and a null data removes every entry. Honors the If-Match header like the full update.",
+		Request:     docs.DocJsonPayload[requestWebDataPatch](),
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[web.WebData](),
+			"400": docs.DocText(),
+			"409": docs.DocJsonPayload[web.WebData](),
+			"413": docs.DocText(),
+			"422": docs.DocJsonPayload[responseFieldErrors](),
+		},
+	}
+}
+
+func (c *ControllerLogin) patchWebData(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+
+	opts := router.InputOpts{
+		Limit:  configuration.Instance().WebDataLimit,
+		Strict: true,
+	}
+
+	input, res := router.InputJsonWithOpts[requestWebDataPatch](w, r, opts)
+	if res != nil {
+		return *res
+	}
 
-	token, err := auth.GenerateJWT(sess.Username)
+	entries, erase, err := input.entries()
 	if err != nil {
-		return "", "", err
+		return result.TextErr(http.StatusBadRequest, "the data must be an object of string or null values")
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     AUTH_COOKIE,
-		Value:    token,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteLaxMode,
+	output, ok, err := c.managerWeb.ModifyIf(username, matchesWebData(r), func(data map[string]string) error {
+		if erase {
+			clear(data)
+		}
+		for k, v := range entries {
+			if v == nil {
+				delete(data, k)
+				continue
+			}
+			if err := checkWebDataValue(k, *v); err != nil {
+				return err
+			}
+			data[k] = *v
+		}
+		return nil
 	})
 
-	refresh := sess.Refresh
-	if _, err := auth.ValidateJWT(refresh); err != nil {
-		refresh, err = auth.GenerateRefreshJWT(sess.Username)
-		if err != nil {
-			return "", "", err
+	return webDataResult(w, output, ok, err)
+}
+
+func (c *ControllerLogin) docFindWebHistory() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Lists the previous revisions of the currently authenticated user's web data, the newest first, with the size of each one and how much the next change grew or shrank it.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]revision.Revision](),
+		},
+	}
+}
+
+func (c *ControllerLogin) findWebHistory(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return result.JsonOk(c.managerWeb.History(findUser(ctx)))
+}
+
+func (c *ControllerLogin) docRestoreWebRevision() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Replaces the currently authenticated user's web data with one of its revisions. The replaced data becomes a new revision, so the restore can be undone. Honors the If-Match header like the full update.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(WEB_REVISION, WEB_REVISION_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[web.WebData](),
+			"400": docs.DocText(),
+			"404": docs.DocText(),
+			"409": docs.DocJsonPayload[web.WebData](),
+			"422": docs.DocJsonPayload[responseFieldErrors](),
+		},
+	}
+}
+
+func (c *ControllerLogin) restoreWebRevision(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+
+	number, err := strconv.ParseInt(r.PathValue(WEB_REVISION), 10, 64)
+	if err != nil {
+		return result.TextErr(http.StatusBadRequest, "the revision must be a number")
+	}
+
+	found, ok := c.managerWeb.FindRevision(username, number)
+	if !ok {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	output, ok, err := c.managerWeb.RestoreIf(username, found, matchesWebData(r))
+
+	return webDataResult(w, output, ok, err)
+}
+
+func (c *ControllerLogin) docFindWebDataKey() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Gets the value of a single key of the currently authenticated user's web data. The ETag header identifies the version of the whole data.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(WEB_KEY, WEB_KEY_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocText(),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerLogin) findWebDataKey(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+	webData, _ := c.managerWeb.FindByOwner(username)
+
+	value, ok := webData.Data[r.PathValue(WEB_KEY)]
+	if !ok {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	writeETag(w, webData.ETag())
+
+	return result.Ok(value)
+}
+
+func (c *ControllerLogin) docResolveWebDataKey() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Sets the value of a single key of the currently authenticated user's web data, leaving the other keys untouched. Honors the If-Match header like the full update.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(WEB_KEY, WEB_KEY_DESCRIPTION),
+		},
+		Request: docs.DocText("Value"),
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[web.WebData](),
+			"409": docs.DocJsonPayload[web.WebData](),
+			"413": docs.DocText(),
+			"422": docs.DocJsonPayload[responseFieldErrors](),
+		},
+	}
+}
+
+func (c *ControllerLogin) resolveWebDataKey(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+	key := r.PathValue(WEB_KEY)
+
+	value, res := router.InputText(r)
+	if res != nil {
+		return *res
+	}
+
+	output, ok, err := c.managerWeb.ModifyIf(username, matchesWebData(r), func(data map[string]string) error {
+		if err := checkWebDataValue(key, value); err != nil {
+			return err
 		}
-		sessions.Refresh(sess, refresh)
+		data[key] = value
+		return nil
+	})
+
+	return webDataResult(w, output, ok, err)
+}
+
+func (c *ControllerLogin) docDeleteWebDataKey() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Removes a single key of the currently authenticated user's web data. Honors the If-Match header like the full update.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(WEB_KEY, WEB_KEY_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[web.WebData](),
+			"409": docs.DocJsonPayload[web.WebData](),
+		},
+	}
+}
+
+func (c *ControllerLogin) deleteWebDataKey(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+	key := r.PathValue(WEB_KEY)
+
+	output, ok, err := c.managerWeb.ModifyIf(username, matchesWebData(r), func(data map[string]string) error {
+		delete(data, key)
+		return nil
+	})
+
+	return webDataResult(w, output, ok, err)
+}
+
+func (c *ControllerLogin) docFindWebNamespace() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Gets the JSON value of a namespace of the currently authenticated user's web data. The ETag header identifies the version of the whole data.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(NAMESPACE, NAMESPACE_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[web.Value](),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerLogin) findWebNamespace(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+	webData, _ := c.managerWeb.FindByOwner(username)
+
+	value, ok := webData.Namespaces[r.PathValue(NAMESPACE)]
+	if !ok {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	writeETag(w, webData.ETag())
+
+	return result.JsonOk(value)
+}
+
+func (c *ControllerLogin) docResolveWebNamespace() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Replaces the JSON value of a namespace of the currently authenticated user's web data. The value must satisfy the schema and quota of the namespace. Honors the If-Match header like the full update.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(NAMESPACE, NAMESPACE_DESCRIPTION),
+		},
+		Request: docs.DocJsonPayload[web.Value](),
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[web.WebData](),
+			"400": docs.DocText(),
+			"409": docs.DocJsonPayload[web.WebData](),
+			"422": docs.DocJsonPayload[responseFieldErrors](),
+		},
+	}
+}
+
+func (c *ControllerLogin) resolveWebNamespace(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+
+	body, res := router.InputText(r)
+	if res != nil {
+		return *res
+	}
+
+	value, err := web.MakeValue([]byte(body))
+	if err != nil {
+		return result.Err(http.StatusBadRequest, err)
+	}
+
+	output, ok, err := c.managerWeb.ModifyNamespaceIf(username, r.PathValue(NAMESPACE), matchesWebData(r), func(web.Value) (web.Value, error) {
+		return value, nil
+	})
+
+	return webDataResult(w, output, ok, err)
+}
+
+func (c *ControllerLogin) docPatchWebNamespace() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Applies a JSON Merge Patch to the value of a namespace of the currently authenticated user's web data. The result must satisfy the schema and quota of the namespace. Honors the If-Match header like the full update.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(NAMESPACE, NAMESPACE_DESCRIPTION),
+		},
+		Request: docs.DocJsonPayload[web.Value](),
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[web.WebData](),
+			"400": docs.DocText(),
+			"409": docs.DocJsonPayload[web.WebData](),
+			"422": docs.DocJsonPayload[responseFieldErrors](),
+		},
+	}
+}
+
+func (c *ControllerLogin) patchWebNamespace(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+
+	body, res := router.InputText(r)
+	if res != nil {
+		return *res
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     REFRESH_COOKIE,
-		Value:    refresh,
-		Path:     fmt.Sprintf("%srefresh", BASE_PATH),
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteLaxMode,
+	if !json.Valid([]byte(body)) {
+		return result.TextErr(http.StatusBadRequest, "the patch is not valid JSON")
+	}
+
+	output, ok, err := c.managerWeb.ModifyNamespaceIf(username, r.PathValue(NAMESPACE), matchesWebData(r), func(value web.Value) (web.Value, error) {
+		return value.Merge([]byte(body))
 	})
 
+	return webDataResult(w, output, ok, err)
+}
+
+func (c *ControllerLogin) docDeleteWebNamespace() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Removes a namespace of the currently authenticated user's web data. Honors the If-Match header like the full update.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(NAMESPACE, NAMESPACE_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[web.WebData](),
+			"409": docs.DocJsonPayload[web.WebData](),
+		},
+	}
+}
+
+func (c *ControllerLogin) deleteWebNamespace(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+
+	output, ok, err := c.managerWeb.ModifyNamespaceIf(username, r.PathValue(NAMESPACE), matchesWebData(r), func(web.Value) (web.Value, error) {
+		return "", nil
+	})
+
+	return webDataResult(w, output, ok, err)
+}
+
+func (c *ControllerLogin) closeDevice(r *http.Request) (string, bool) {
+	cookie, err := readCookie(r, AUTH_COOKIE, ROOT_PATH)
+	if err != nil {
+		return "", false
+	}
+
+	claims, err := auth.ValidateJWT(cookie.Value, auth.TOKEN_ACCESS)
+	if err != nil && !auth.IsExpired(err) {
+		return "", false
+	}
+
+	device, ok := c.managerDevice.Find(claims.Username, claims.Session)
+	if !ok {
+		return "", false
+	}
+
+	c.managerDevice.Revoke(claims.Username, device)
+
+	return claims.Username, true
+}
+
+func matchesWebData(r *http.Request) func(current *web.WebData) bool {
+	return func(current *web.WebData) bool {
+		return matchesETag(r, current.ETag())
+	}
+}
+
+func webDataResult(w http.ResponseWriter, output *web.WebData, ok bool, err error) result.Result {
+	writeETag(w, output.ETag())
+
+	if !ok {
+		return jsonStatus(w, http.StatusConflict, output)
+	}
+
+	var invalid manager.WebDataError
+	if errors.As(err, &invalid) {
+		return jsonStatus(w, http.StatusUnprocessableEntity, makeResponseNamespaceErrors(invalid.Violations))
+	}
+
+	var exceeded webDataValueError
+	if errors.As(err, &exceeded) {
+		return result.Err(http.StatusRequestEntityTooLarge, err)
+	}
+
+	if err != nil {
+		return result.Err(http.StatusInternalServerError, err)
+	}
+
+	return result.JsonOk(output)
+}
+
+// webDataValueError reports a web data value larger than the key limit.
+type webDataValueError struct {
+	key   string
+	limit int64
+}
+
+func (e webDataValueError) Error() string {
+	return fmt.Sprintf("the value of %q exceeds the limit of %d bytes", e.key, e.limit)
+}
+
+func checkWebDataValue(key, value string) error {
+	limit := configuration.Instance().WebDataKeyLimit
+	if limit > 0 && int64(len(value)) > limit {
+		return webDataValueError{key: key, limit: limit}
+	}
+	return nil
+}
+
+// openSession starts a new session family for the device of the request and
+// sets the session cookies.
+func openSession(w http.ResponseWriter, r *http.Request, managerDevice *manager.ManagerDevice, sess *domain_session.Session, remember bool) error {
+	device := managerDevice.Open(sess.Username, r.UserAgent(), clientAddress(r), remember)
+
+	if _, _, err := defineSession(w, managerDevice, sess, device); err != nil {
+		return err
+	}
+
+	session.InstanceManagerSession().Visited(sess)
+
+	return nil
+}
+
+// defineSession issues the tokens of the session and sets them as cookies.
+// The refresh cookie of a remembered session outlives the browser session.
+func defineSession(w http.ResponseWriter, managerDevice *manager.ManagerDevice, sess *domain_session.Session, device *device.Device) (string, string, error) {
+	accessLifetime, refreshLifetime := managerDevice.Lifetimes(device)
+
+	token, err := auth.GenerateJWT(sess.Username, device.Id, device.Access, accessLifetime)
+	if err != nil {
+		return "", "", err
+	}
+
+	refresh, err := auth.GenerateRefreshJWT(sess.Username, device.Id, device.Refresh, refreshLifetime)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := defineCsrf(w); err != nil {
+		return "", "", err
+	}
+
+	writeCookie(w, AUTH_COOKIE, token, ROOT_PATH, 0, true)
+	maxAge := 0
+	if device.Remember {
+		maxAge = int(refreshLifetime.Seconds())
+	}
+
+	writeCookie(w, REFRESH_COOKIE, refresh, REFRESH_PATH, maxAge, true)
+
 	return token, refresh, nil
 }
 
 func closeSession(w http.ResponseWriter) http.ResponseWriter {
-	http.SetCookie(w, &http.Cookie{
-		Name:     AUTH_COOKIE,
-		Value:    "",
-		Path:     "/",
-		Expires:  time.Unix(0, 0),
-		MaxAge:   -1,
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteLaxMode,
-	})
+	expireCookie(w, AUTH_COOKIE, ROOT_PATH, true)
 	return w
 }
 
 func eraseSession(w http.ResponseWriter) http.ResponseWriter {
 	closeSession(w)
-	http.SetCookie(w, &http.Cookie{
-		Name:     REFRESH_COOKIE,
-		Value:    "",
-		Path:     fmt.Sprintf("%srefresh", BASE_PATH),
-		Expires:  time.Unix(0, 0),
-		MaxAge:   -1,
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteLaxMode,
-	})
+	expireCookie(w, REFRESH_COOKIE, REFRESH_PATH, true)
+	eraseCsrf(w)
 	return w
 }
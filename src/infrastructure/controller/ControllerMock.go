@@ -14,7 +14,10 @@ import (
 	"github.com/Rafael24595/go-api-core/src/domain/mock/swr"
 	"github.com/Rafael24595/go-api-core/src/domain/token"
 	"github.com/Rafael24595/go-api-core/src/infrastructure/dto"
+	render_manager "github.com/Rafael24595/go-api-render/src/application/manager"
 	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-collections/collection"
 	"github.com/Rafael24595/go-log/log"
 	"github.com/Rafael24595/go-web/router"
@@ -34,53 +37,56 @@ const ID_END_POINT_DESCRIPTION = "End point ID"
 const SWR_INPUT_DESCRIPTION = "SWR sentence"
 
 type ControllerMock struct {
-	router          *router.Router
+	router          *SecureRouter
 	managerToken    *manager.ManagerToken
 	managerEndPoint *manager.ManagerEndPoint
 	managerMetrics  *manager.ManagerMetrics
+	managerAudit    *render_manager.ManagerAudit
 }
 
 func NewControllerMock(
-	router *router.Router,
+	router *SecureRouter,
 	managerToken *manager.ManagerToken,
 	managerEndPoint *manager.ManagerEndPoint,
 	managerMetrics *manager.ManagerMetrics,
+	managerAudit *render_manager.ManagerAudit,
 ) ControllerMock {
 	instance := ControllerMock{
 		router:          router,
 		managerToken:    managerToken,
 		managerEndPoint: managerEndPoint,
 		managerMetrics:  managerMetrics,
+		managerAudit:    managerAudit,
 	}
 
 	router.
-		RouteDocument(http.MethodPost, instance.bridgeStpToCnd, "bridge/mock/response/to/step", instance.docBridgeStpToCnd()).
-		RouteDocument(http.MethodPost, instance.bridgeCndToStp, "bridge/mock/response/from/step", instance.docBridgeCndToStp()).
-		RouteDocument(http.MethodGet, instance.bridgeEndToReq, "bridge/mock/endpoint/{%s}/to/request", instance.docBridgeEndToReq()).
+		RouteDocument(http.MethodPost, role.PERMISSION_MOCK_MANAGE, instance.bridgeStpToCnd, "bridge/mock/response/to/step", instance.docBridgeStpToCnd()).
+		RouteDocument(http.MethodPost, role.PERMISSION_MOCK_MANAGE, instance.bridgeCndToStp, "bridge/mock/response/from/step", instance.docBridgeCndToStp()).
+		RouteDocument(http.MethodGet, role.PERMISSION_MOCK_MANAGE, instance.bridgeEndToReq, "bridge/mock/endpoint/{%s}/to/request", instance.docBridgeEndToReq()).
 		//
-		RouteDocument(http.MethodPut, instance.sortEndPoint, "sort/mock/endpoint", instance.docSortEndPoint()).
+		RouteDocument(http.MethodPut, role.PERMISSION_MOCK_MANAGE, instance.sortEndPoint, "sort/mock/endpoint", instance.docSortEndPoint()).
 		//
-		RouteDocument(http.MethodGet, instance.exportAll, "export/mock/endpoint", instance.docExportAll()).
-		RouteDocument(http.MethodPost, instance.exportMany, "export/mock/endpoint", instance.docExportMany()).
-		RouteDocument(http.MethodPost, instance.importMany, "import/mock/endpoint", instance.docImportMany()).
+		RouteDocument(http.MethodGet, role.PERMISSION_MOCK_MANAGE, instance.exportAll, "export/mock/endpoint", instance.docExportAll()).
+		RouteDocument(http.MethodPost, role.PERMISSION_MOCK_MANAGE, instance.exportMany, "export/mock/endpoint", instance.docExportMany()).
+		RouteDocument(http.MethodPost, role.PERMISSION_MOCK_MANAGE, instance.importMany, "import/mock/endpoint", instance.docImportMany()).
 		//
-		RouteDocument(http.MethodGet, instance.findAll, "mock/endpoint", instance.docFindAll()).
-		RouteDocument(http.MethodGet, instance.find, "mock/endpoint/{%s}", instance.docFind()).
-		RouteDocument(http.MethodPost, instance.insert, "mock/endpoint", instance.docInsert()).
-		RouteDocument(http.MethodDelete, instance.remove, "mock/endpoint/{%s}", instance.docRemove()).
+		RouteDocument(http.MethodGet, role.PERMISSION_MOCK_MANAGE, instance.findAll, "mock/endpoint", instance.docFindAll()).
+		RouteDocument(http.MethodGet, role.PERMISSION_MOCK_MANAGE, instance.find, "mock/endpoint/{%s}", instance.docFind()).
+		RouteDocument(http.MethodPost, role.PERMISSION_MOCK_MANAGE, instance.insert, "mock/endpoint", instance.docInsert()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_MOCK_MANAGE, instance.remove, "mock/endpoint/{%s}", instance.docRemove()).
 		//
-		RouteDocument(http.MethodGet, instance.findMetrics, "mock/metrics/endpoint/{%s}", instance.docFindMetrics()).
-		RouteDocument(http.MethodDelete, instance.removeMetrics, "mock/metrics/endpoint/{%s}", instance.docRemoveMetrics()).
+		RouteDocument(http.MethodGet, role.PERMISSION_MOCK_MANAGE, instance.findMetrics, "mock/metrics/endpoint/{%s}", instance.docFindMetrics()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_MOCK_MANAGE, instance.removeMetrics, "mock/metrics/endpoint/{%s}", instance.docRemoveMetrics()).
 		//
-		RouteDocument(http.MethodGet, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
-		RouteDocument(http.MethodHead, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
-		RouteDocument(http.MethodPost, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
-		RouteDocument(http.MethodPut, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
-		RouteDocument(http.MethodPatch, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
-		RouteDocument(http.MethodDelete, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
-		RouteDocument(http.MethodConnect, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
-		RouteDocument(http.MethodOptions, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
-		RouteDocument(http.MethodTrace, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall())
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
+		RouteDocument(http.MethodHead, role.PERMISSION_NONE, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
+		RouteDocument(http.MethodPut, role.PERMISSION_NONE, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
+		RouteDocument(http.MethodPatch, role.PERMISSION_NONE, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_NONE, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
+		RouteDocument(http.MethodConnect, role.PERMISSION_NONE, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
+		RouteDocument(http.MethodOptions, role.PERMISSION_NONE, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall()).
+		RouteDocument(http.MethodTrace, role.PERMISSION_NONE, instance.call, "mock/call/{%s}/{%s...}", instance.docMockCall())
 
 	return instance
 }
@@ -429,6 +435,7 @@ func (c *ControllerMock) call(w http.ResponseWriter, r *http.Request, ctx *route
 	}
 
 	if res := c.authRequest(r, owner, endPoint); res.Err() {
+		recordEvent(c.managerAudit, r, audit.KIND_MOCK_REJECTED, "", owner, fmt.Sprintf("%s %s", method, point))
 		return res
 	}
 
@@ -468,18 +475,14 @@ func (c *ControllerMock) authRequest(r *http.Request, owner string, endPoint *mo
 		return result.Next()
 	}
 
-	cookie, err := r.Cookie(AUTH_TOKEN)
-	if err != nil {
-		return result.Err(http.StatusUnauthorized, err)
-	}
-
-	if cookie == nil {
+	credential, ok := findMockToken(r)
+	if !ok {
 		return result.Reject(http.StatusUnauthorized)
 	}
 
-	tkn, ok := c.managerToken.FindByToken(owner, cookie.Value)
+	tkn, ok := c.managerToken.FindByToken(owner, credential.value)
 	if !ok {
-		return result.Err(http.StatusForbidden, err)
+		return result.Reject(http.StatusForbidden)
 	}
 
 	if tkn.IsExipred() {
@@ -516,6 +519,24 @@ func (c *ControllerMock) findResponse(r *http.Request, endPoint *mock.EndPoint)
 	return response, nil
 }
 
+// findMockToken resolves the API token of a mock request. Mocked APIs often
+// expect their own Authorization header, so it has the lowest precedence here.
+func findMockToken(r *http.Request) (credential, bool) {
+	if key := strings.TrimSpace(r.Header.Get(API_KEY_HEADER)); key != "" {
+		return credential{value: key}, true
+	}
+
+	if cookie, err := readCookie(r, AUTH_TOKEN, ROOT_PATH); err == nil {
+		return credential{value: cookie.Value, cookie: true}, true
+	}
+
+	if bearer, ok := findBearer(r); ok && bearer != "" && !isJwt(bearer) {
+		return credential{value: bearer}, true
+	}
+
+	return credential{}, false
+}
+
 func endPointToRequest(host string, endPoint *mock.EndPoint) *action.Request {
 	server := mockEndPointPath(host, endPoint)
 	request := mock.ToRequest(server, endPoint)
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Rafael24595/go-api-render/src/application/manager"
+	"github.com/Rafael24595/go-api-render/src/domain/namespace"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+const NAMESPACE = "namespace"
+const NAMESPACE_DESCRIPTION = "Web data namespace"
+
+type ControllerNamespace struct {
+	router           *SecureRouter
+	managerNamespace *manager.ManagerNamespace
+}
+
+func NewControllerNamespace(
+	router *SecureRouter,
+	managerNamespace *manager.ManagerNamespace,
+) ControllerNamespace {
+	instance := ControllerNamespace{
+		router:           router,
+		managerNamespace: managerNamespace,
+	}
+
+	router.
+		RouteDocument(http.MethodGet, role.PERMISSION_WEB_MANAGE, instance.findAll, "admin/web/namespaces", instance.docFindAll()).
+		RouteDocument(http.MethodGet, role.PERMISSION_WEB_MANAGE, instance.find, "admin/web/namespaces/{%s}", instance.docFind()).
+		RouteDocument(http.MethodPut, role.PERMISSION_WEB_MANAGE, instance.resolve, "admin/web/namespaces/{%s}", instance.docResolve()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_WEB_MANAGE, instance.delete, "admin/web/namespaces/{%s}", instance.docDelete())
+
+	return instance
+}
+
+func (c *ControllerNamespace) docFindAll() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Lists the registered web data namespaces with their schemas and quotas.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]namespace.Namespace](),
+		},
+	}
+}
+
+func (c *ControllerNamespace) findAll(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return result.JsonOk(c.managerNamespace.FindAll())
+}
+
+func (c *ControllerNamespace) docFind() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Gets a registered web data namespace with its schema and quota.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(NAMESPACE, NAMESPACE_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[namespace.Namespace](),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerNamespace) find(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	found, ok := c.managerNamespace.Find(r.PathValue(NAMESPACE))
+	if !ok {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	return result.JsonOk(found)
+}
+
+func (c *ControllerNamespace) docResolve() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Registers or replaces a web data namespace. The schema is a JSON Schema the value of the namespace must satisfy on every update; keywords that cannot be enforced, such as references or formats, are rejected. A quota of 0 falls back to the default one.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(NAMESPACE, NAMESPACE_DESCRIPTION),
+		},
+		Request: docs.DocJsonPayload[requestNamespace](),
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[namespace.Namespace](),
+			"422": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerNamespace) resolve(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	request, res := router.InputJson[requestNamespace](r)
+	if res != nil {
+		return *res
+	}
+
+	resolved, err := c.managerNamespace.Resolve(findUser(ctx), r.PathValue(NAMESPACE), request.Description, request.Schema, request.Quota)
+	if err != nil {
+		return result.Err(http.StatusUnprocessableEntity, err)
+	}
+
+	return result.JsonOk(resolved)
+}
+
+func (c *ControllerNamespace) docDelete() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Deletes a web data namespace. The data the users keep in it is no longer validated and falls back to the default quota.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(NAMESPACE, NAMESPACE_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"202": docs.DocText(),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerNamespace) delete(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	if deleted := c.managerNamespace.Delete(r.PathValue(NAMESPACE)); deleted == nil {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	return result.Accept(http.StatusAccepted)
+}
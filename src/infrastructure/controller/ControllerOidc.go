@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Rafael24595/go-api-core/src/application/session"
+	domain_session "github.com/Rafael24595/go-api-core/src/domain/session"
+	"github.com/Rafael24595/go-api-render/src/application/manager"
+	"github.com/Rafael24595/go-api-render/src/commons/oidc"
+	"github.com/Rafael24595/go-api-render/src/domain/account"
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-log/log"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+const OIDC_STATE_COOKIE = "go_api_oidc_state"
+const OIDC_STATE_COOKIE_DESCRIPTION = "OpenID Connect authorization state"
+
+const OIDC_STATE_PATH = BASE_PATH + "login/oidc"
+
+const QUERY_CODE = "code"
+const QUERY_CODE_DESCRIPTION = "Authorization code issued by the identity provider"
+
+const QUERY_STATE = "state"
+const QUERY_STATE_DESCRIPTION = "Authorization state"
+
+type ControllerOidc struct {
+	router         *SecureRouter
+	provider       *oidc.Provider
+	managerDevice  *manager.ManagerDevice
+	managerAccount *manager.ManagerAccount
+	managerAudit   *manager.ManagerAudit
+}
+
+func NewControllerOidc(
+	router *SecureRouter,
+	provider *oidc.Provider,
+	managerDevice *manager.ManagerDevice,
+	managerAccount *manager.ManagerAccount,
+	managerAudit *manager.ManagerAudit,
+) ControllerOidc {
+	instance := ControllerOidc{
+		router:         router,
+		provider:       provider,
+		managerDevice:  managerDevice,
+		managerAccount: managerAccount,
+		managerAudit:   managerAudit,
+	}
+
+	router.
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.authorize, "login/oidc", instance.docAuthorize()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.callback, "login/oidc/callback", instance.docCallback())
+
+	return instance
+}
+
+func (c *ControllerOidc) docAuthorize() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Redirects the browser to the identity provider to start an OpenID Connect login.",
+		Responses: docs.DocResponses{
+			"302": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerOidc) authorize(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	state, location, err := c.provider.Authorize()
+	if err != nil {
+		return result.Err(http.StatusBadGateway, err)
+	}
+
+	writeCookie(w, OIDC_STATE_COOKIE, state, OIDC_STATE_PATH, int((10 * time.Minute).Seconds()), true)
+
+	http.Redirect(w, r, location, http.StatusFound)
+	return result.Continue()
+}
+
+func (c *ControllerOidc) docCallback() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Completes the OpenID Connect login, provisions the user on its first access and establishes a session.",
+		Query: docs.DocParameters{
+			QUERY_CODE:  QUERY_CODE_DESCRIPTION,
+			QUERY_STATE: QUERY_STATE_DESCRIPTION,
+		},
+		Cookies: docs.DocParameters{
+			OIDC_STATE_COOKIE: OIDC_STATE_COOKIE_DESCRIPTION,
+		},
+		Responses: docs.DocResponses{
+			"302": docs.DocText(),
+			"401": docs.DocText(),
+			"403": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerOidc) callback(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	query := r.URL.Query()
+
+	cookie, err := readCookie(r, OIDC_STATE_COOKIE, OIDC_STATE_PATH)
+	expireCookie(w, OIDC_STATE_COOKIE, OIDC_STATE_PATH, true)
+	if err != nil || cookie.Value == "" || cookie.Value != query.Get(QUERY_STATE) {
+		return result.TextErr(http.StatusUnauthorized, "the authorization state does not match")
+	}
+
+	if reason := query.Get("error"); reason != "" {
+		return result.TextErr(http.StatusUnauthorized, fmt.Sprintf("the identity provider rejected the login: %s", reason))
+	}
+
+	identity, err := c.provider.Exchange(query.Get(QUERY_STATE), query.Get(QUERY_CODE))
+	if err != nil {
+		recordEvent(c.managerAudit, r, audit.KIND_LOGIN_FAILED, "", "", err.Error())
+		return result.Err(http.StatusUnauthorized, err)
+	}
+
+	session, err := c.provision(r, identity)
+	if err != nil {
+		recordEvent(c.managerAudit, r, audit.KIND_LOGIN_FAILED, identity.Username, identity.Username, err.Error())
+		return result.Err(http.StatusUnauthorized, err)
+	}
+
+	if c.managerAccount.IsDisabled(session.Username) {
+		return result.TextErr(http.StatusForbidden, "the account is disabled")
+	}
+
+	if err := openSession(w, r, c.managerDevice, session, false); err != nil {
+		return result.Err(http.StatusUnauthorized, err)
+	}
+
+	recordEvent(c.managerAudit, r, audit.KIND_LOGIN, session.Username, session.Username, account.PROVIDER_OIDC)
+
+	http.Redirect(w, r, c.provider.Config().Landing, http.StatusFound)
+	return result.Continue()
+}
+
+// provision returns the user linked to the identity, creating it on its first
+// login. Users are matched by the issuer and subject of the identity, never by
+// the username, so an existing user with the same name is not taken over.
+func (c *ControllerOidc) provision(r *http.Request, identity *oidc.Identity) (*domain_session.Session, error) {
+	config := c.provider.Config()
+	sessions := session.InstanceManagerSession()
+
+	if linked, ok := c.managerAccount.FindByIdentity(identity.Issuer, identity.Subject); ok {
+		existing, ok := sessions.Find(linked.Owner)
+		if !ok {
+			return nil, fmt.Errorf("the user %q linked to the identity does not exist", linked.Owner)
+		}
+		c.synchronize(r, existing, identity)
+		return existing, nil
+	}
+
+	if _, ok := sessions.Find(identity.Username); ok {
+		return nil, fmt.Errorf("the user %q already exists and is not linked to the identity provider", identity.Username)
+	}
+
+	isAdmin := config.AdminGroup != "" && slices.Contains(identity.Groups, config.AdminGroup)
+
+	provisioner, ok := sessions.Find(config.Provisioner)
+	if !ok {
+		return nil, fmt.Errorf("the provisioning user %q does not exist", config.Provisioner)
+	}
+
+	roles := make([]domain_session.Role, 0)
+	if isAdmin {
+		roles = append(roles, domain_session.ROLE_ADMIN)
+	}
+
+	// Provisioned users authenticate through the identity provider only, so
+	// their local password is random and the first-time update is skipped.
+	secret, err := randomSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := sessions.Insert(provisioner, identity.Username, secret, roles); err != nil {
+		return nil, err
+	}
+
+	replacement, err := randomSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := sessions.Verify(identity.Username, secret, replacement, replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, errors.New("the user could not be provisioned")
+	}
+
+	c.managerAccount.Link(identity.Username, identity.Issuer, identity.Subject)
+
+	recordEvent(c.managerAudit, r, audit.KIND_USER_CREATE, config.Provisioner, identity.Username, account.PROVIDER_OIDC)
+
+	log.Messagef("The user %q has been provisioned from the identity provider", identity.Username)
+
+	return session, nil
+}
+
+// synchronize grants or revokes ROLE_ADMIN so it follows the group claim of
+// the identity provider on every login.
+func (c *ControllerOidc) synchronize(r *http.Request, existing *domain_session.Session, identity *oidc.Identity) {
+	config := c.provider.Config()
+	if config.AdminGroup == "" {
+		return
+	}
+
+	isAdmin := slices.Contains(identity.Groups, config.AdminGroup)
+	if isAdmin == c.managerAccount.Resolve(existing).HasRole(domain_session.ROLE_ADMIN) {
+		return
+	}
+
+	if isAdmin {
+		c.managerAccount.Grant(existing.Username, domain_session.ROLE_ADMIN)
+		recordEvent(c.managerAudit, r, audit.KIND_ROLE_GRANT, config.Provisioner, existing.Username, string(domain_session.ROLE_ADMIN))
+	} else {
+		c.managerAccount.Revoke(existing.Username, domain_session.ROLE_ADMIN)
+		recordEvent(c.managerAudit, r, audit.KIND_ROLE_REVOKE, config.Provisioner, existing.Username, string(domain_session.ROLE_ADMIN))
+	}
+
+	log.Messagef("The %q role of %q has been synchronized with the %q group claim of the identity provider", domain_session.ROLE_ADMIN, existing.Username, config.AdminGroup)
+}
+
+func randomSecret() (string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
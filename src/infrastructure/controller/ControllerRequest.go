@@ -7,20 +7,21 @@ import (
 	"github.com/Rafael24595/go-api-core/src/application/session"
 	action_domain "github.com/Rafael24595/go-api-core/src/domain/action"
 	"github.com/Rafael24595/go-api-core/src/infrastructure/dto"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
 )
 
 type ControllerRequest struct {
-	router             *router.Router
+	router             *SecureRouter
 	managerRequest     *manager.ManagerRequest
 	managerCollection  *manager.ManagerCollection
 	managerSessionData *session.ManagerSessionData
 }
 
 func NewControllerRequest(
-	router *router.Router,
+	router *SecureRouter,
 	managerRequest *manager.ManagerRequest,
 	managerCollection *manager.ManagerCollection,
 	managerSessionData *session.ManagerSessionData,
@@ -33,15 +34,15 @@ func NewControllerRequest(
 	}
 
 	router.
-		RouteDocument(http.MethodPut, instance.sort, "sort/request", instance.docSort()).
-		RouteDocument(http.MethodGet, instance.exportAll, "export/request", instance.docExportAll()).
-		RouteDocument(http.MethodPost, instance.exportMany, "export/request", instance.docExportMany()).
-		RouteDocument(http.MethodPost, instance.importMany, "import/request", instance.docImportMany()).
-		RouteDocument(http.MethodGet, instance.findAll, "request", instance.docFindAll()).
-		RouteDocument(http.MethodPost, instance.insert, "request", instance.docInsert()).
-		RouteDocument(http.MethodPut, instance.update, "request", instance.docUpdate()).
-		RouteDocument(http.MethodGet, instance.find, "request/{%s}", instance.docFind()).
-		RouteDocument(http.MethodDelete, instance.delete, "request/{%s}", instance.docDelete())
+		RouteDocument(http.MethodPut, role.PERMISSION_COLLECTIONS_WRITE, instance.sort, "sort/request", instance.docSort()).
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.exportAll, "export/request", instance.docExportAll()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_READ, instance.exportMany, "export/request", instance.docExportMany()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_WRITE, instance.importMany, "import/request", instance.docImportMany()).
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.findAll, "request", instance.docFindAll()).
+		RouteDocument(http.MethodPost, role.PERMISSION_COLLECTIONS_WRITE, instance.insert, "request", instance.docInsert()).
+		RouteDocument(http.MethodPut, role.PERMISSION_COLLECTIONS_WRITE, instance.update, "request", instance.docUpdate()).
+		RouteDocument(http.MethodGet, role.PERMISSION_COLLECTIONS_READ, instance.find, "request/{%s}", instance.docFind()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_COLLECTIONS_WRITE, instance.delete, "request/{%s}", instance.docDelete())
 
 	return instance
 }
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Rafael24595/go-api-render/src/application/manager"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+type ControllerRole struct {
+	router         *SecureRouter
+	managerRole    *manager.ManagerRole
+	managerAccount *manager.ManagerAccount
+}
+
+func NewControllerRole(
+	router *SecureRouter,
+	managerRole *manager.ManagerRole,
+	managerAccount *manager.ManagerAccount,
+) ControllerRole {
+	instance := ControllerRole{
+		router:         router,
+		managerRole:    managerRole,
+		managerAccount: managerAccount,
+	}
+
+	router.
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.findOwn, "user/permissions", instance.docFindOwn()).
+		//
+		RouteDocument(http.MethodGet, role.PERMISSION_ROLES_MANAGE, instance.permissions, "admin/permissions", instance.docPermissions()).
+		RouteDocument(http.MethodGet, role.PERMISSION_ROLES_MANAGE, instance.findAll, "admin/roles", instance.docFindAll()).
+		RouteDocument(http.MethodGet, role.PERMISSION_ROLES_MANAGE, instance.find, "admin/roles/{%s}", instance.docFind()).
+		RouteDocument(http.MethodPut, role.PERMISSION_ROLES_MANAGE, instance.resolve, "admin/roles/{%s}", instance.docResolve()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_ROLES_MANAGE, instance.delete, "admin/roles/{%s}", instance.docDelete())
+
+	return instance
+}
+
+func (c *ControllerRole) docFindOwn() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Lists the permissions granted to the authenticated user by its roles.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]role.Permission](),
+		},
+	}
+}
+
+func (c *ControllerRole) findOwn(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	session, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	return result.JsonOk(c.managerRole.Permissions(session.Roles))
+}
+
+func (c *ControllerRole) docPermissions() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Lists the permissions that can be bundled in a role.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]role.Permission](),
+		},
+	}
+}
+
+func (c *ControllerRole) permissions(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return result.JsonOk(role.Permissions())
+}
+
+func (c *ControllerRole) docFindAll() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Lists the roles with the permissions they bundle.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]role.Role](),
+		},
+	}
+}
+
+func (c *ControllerRole) findAll(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return result.JsonOk(c.managerRole.FindAll())
+}
+
+func (c *ControllerRole) docFind() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Gets a role with the permissions it bundles.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(ROLE, ROLE_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[role.Role](),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerRole) find(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	found, ok := c.managerRole.Find(r.PathValue(ROLE))
+	if !ok {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	return result.JsonOk(found)
+}
+
+func (c *ControllerRole) docResolve() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Creates or replaces a role. The admin role cannot be modified.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(ROLE, ROLE_DESCRIPTION),
+		},
+		Request: docs.DocJsonPayload[requestRole](),
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[role.Role](),
+			"422": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerRole) resolve(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	request, res := router.InputJson[requestRole](r)
+	if res != nil {
+		return *res
+	}
+
+	resolved, err := c.managerRole.Resolve(findUser(ctx), r.PathValue(ROLE), request.Description, request.Permissions)
+	if err != nil {
+		return result.Err(http.StatusUnprocessableEntity, err)
+	}
+
+	return result.JsonOk(resolved)
+}
+
+func (c *ControllerRole) docDelete() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Deletes a role. The users holding it lose its permissions. The built-in roles cannot be deleted.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(ROLE, ROLE_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"202": docs.DocText(),
+			"404": docs.DocText(),
+			"409": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerRole) delete(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	deleted, err := c.managerRole.Delete(r.PathValue(ROLE))
+	if err != nil {
+		return result.Err(http.StatusConflict, err)
+	}
+
+	if deleted == nil {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	return result.Accept(http.StatusAccepted)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/Rafael24595/go-api-render/src/domain/role"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
@@ -17,19 +18,19 @@ const JS_RESOURCE = "js_resource"
 const JS_RESOURCE_DESCRIPTION = "The name of the JavaScript, HTML, or CSS file to be retrieved from the remote Tetris project repository."
 
 type ControllerSecret struct {
-	router *router.Router
+	router *SecureRouter
 	cache  map[string]string
 }
 
-func NewControllerSecret(router *router.Router) ControllerSecret {
+func NewControllerSecret(router *SecureRouter) ControllerSecret {
 	instance := ControllerSecret{
 		router: router,
 		cache:  make(map[string]string),
 	}
 
 	router.
-		RouteDocument(http.MethodGet, instance.jsTetris, "secret/js-tetris/play", instance.docPlay()).
-		RouteDocument(http.MethodGet, instance.jsTetris, "secret/js-tetris/{%s}", instance.docResource())
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.jsTetris, "secret/js-tetris/play", instance.docPlay()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.jsTetris, "secret/js-tetris/{%s}", instance.docResource())
 
 	return instance
 }
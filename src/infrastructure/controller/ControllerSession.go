@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Rafael24595/go-api-render/src/application/manager"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+const ID_SESSION = "session"
+const ID_SESSION_DESCRIPTION = "Session ID"
+
+const USERNAME = "username"
+const USERNAME_DESCRIPTION = "User name"
+
+type ControllerSession struct {
+	router        *SecureRouter
+	managerDevice *manager.ManagerDevice
+}
+
+func NewControllerSession(
+	router *SecureRouter,
+	managerDevice *manager.ManagerDevice,
+) ControllerSession {
+	instance := ControllerSession{
+		router:        router,
+		managerDevice: managerDevice,
+	}
+
+	router.
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.findAll, "user/sessions", instance.docFindAll()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_NONE, instance.revokeAll, "user/sessions", instance.docRevokeAll()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_NONE, instance.revoke, "user/sessions/{%s}", instance.docRevoke()).
+		//
+		RouteDocument(http.MethodGet, role.PERMISSION_USERS_MANAGE, instance.findAllFromUser, "admin/users/{%s}/sessions", instance.docFindAllFromUser()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_USERS_MANAGE, instance.revokeAllFromUser, "admin/users/{%s}/sessions", instance.docRevokeAllFromUser()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_USERS_MANAGE, instance.revokeFromUser, "admin/users/{%s}/sessions/{%s}", instance.docRevokeFromUser())
+
+	return instance
+}
+
+func (c *ControllerSession) docFindAll() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Lists the active sessions of the authenticated user, one per logged device.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]responseSession](),
+		},
+	}
+}
+
+func (c *ControllerSession) findAll(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	user := findUser(ctx)
+	current := findSessionId(ctx)
+
+	devices := c.managerDevice.FindByOwner(user)
+	return result.JsonOk(makeResponseSessions(current, devices...))
+}
+
+func (c *ControllerSession) docRevokeAll() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Revokes every session of the authenticated user except the current one.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]responseSession](),
+		},
+	}
+}
+
+func (c *ControllerSession) revokeAll(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	user := findUser(ctx)
+	current := findSessionId(ctx)
+
+	devices := c.managerDevice.RevokeAll(user, current)
+	return result.JsonOk(makeResponseSessions(current, devices...))
+}
+
+func (c *ControllerSession) docRevoke() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Revokes a session of the authenticated user. Its tokens are rejected immediately.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(ID_SESSION, ID_SESSION_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseSession](),
+		},
+	}
+}
+
+func (c *ControllerSession) revoke(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	user := findUser(ctx)
+	current := findSessionId(ctx)
+
+	id := r.PathValue(ID_SESSION)
+
+	device, ok := c.managerDevice.Find(user, id)
+	if !ok || device.IsRevoked() {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	device = c.managerDevice.Revoke(user, device)
+
+	if device.Id == current {
+		eraseSession(w)
+	}
+
+	return result.JsonOk(makeResponseSession(current, *device))
+}
+
+func (c *ControllerSession) docFindAllFromUser() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Lists the active sessions of any user.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]responseSession](),
+		},
+	}
+}
+
+func (c *ControllerSession) findAllFromUser(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	owner := r.PathValue(USERNAME)
+	current := findSessionId(ctx)
+
+	devices := c.managerDevice.FindByOwner(owner)
+	return result.JsonOk(makeResponseSessions(current, devices...))
+}
+
+func (c *ControllerSession) docRevokeAllFromUser() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Revokes every session of any user.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]responseSession](),
+		},
+	}
+}
+
+func (c *ControllerSession) revokeAllFromUser(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	owner := r.PathValue(USERNAME)
+	current := findSessionId(ctx)
+
+	devices := c.managerDevice.RevokeAll(owner, current)
+	return result.JsonOk(makeResponseSessions(current, devices...))
+}
+
+func (c *ControllerSession) docRevokeFromUser() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Revokes a session of any user.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+			docs.Parameter(ID_SESSION, ID_SESSION_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseSession](),
+		},
+	}
+}
+
+func (c *ControllerSession) revokeFromUser(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	owner := r.PathValue(USERNAME)
+	current := findSessionId(ctx)
+
+	id := r.PathValue(ID_SESSION)
+
+	device, ok := c.managerDevice.Find(owner, id)
+	if !ok || device.IsRevoked() {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	device = c.managerDevice.Revoke(owner, device)
+
+	if device.Id == current {
+		eraseSession(w)
+	}
+
+	return result.JsonOk(makeResponseSession(current, *device))
+}
@@ -1,14 +1,18 @@
 package controller
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 
-	domain_session "github.com/Rafael24595/go-api-core/src/domain/session"
-
 	"github.com/Rafael24595/go-api-core/src/application/command"
 	"github.com/Rafael24595/go-api-core/src/commons/dependency"
+	render_command "github.com/Rafael24595/go-api-render/src/application/command"
+	"github.com/Rafael24595/go-api-render/src/application/manager"
 	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-log/log"
 	"github.com/Rafael24595/go-log/log/record"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
@@ -23,27 +27,40 @@ const CMD_DESCRIPTION = "Command sentence"
 const CMD_QUERY_POSITION = "step"
 const CMD_QUERY_POSITION_DESCRIPTION = "Step value"
 
+const QUERY_KIND = "kind"
+const QUERY_KIND_DESCRIPTION = "Event type"
+
+const QUERY_FROM = "from"
+const QUERY_FROM_DESCRIPTION = "Lower time bound in milliseconds"
+
+const QUERY_TO = "to"
+const QUERY_TO_DESCRIPTION = "Upper time bound in milliseconds"
+
 type ControllerSystem struct {
-	router *router.Router
+	router       *SecureRouter
+	managerAudit *manager.ManagerAudit
 }
 
-func NewControllerSystem(router *router.Router) ControllerSystem {
+func NewControllerSystem(router *SecureRouter, managerAudit *manager.ManagerAudit) ControllerSystem {
 	instance := ControllerSystem{
-		router: router,
+		router:       router,
+		managerAudit: managerAudit,
 	}
 
 	router.
-		RouteDocument(http.MethodGet, instance.log, "system/log", instance.docLog()).
-		RouteDocument(http.MethodPost, instance.cmdExec, "system/cmd/exec", instance.docCmdExec()).
-		RouteDocument(http.MethodPost, instance.cmdComp, "system/cmd/comp", instance.docCmdComp()).
-		RouteDocument(http.MethodGet, instance.metadata, "system/metadata", instance.docMetadata())
+		RouteDocument(http.MethodGet, role.PERMISSION_SYSTEM_LOG, instance.log, "system/log", instance.docLog()).
+		RouteDocument(http.MethodPost, role.PERMISSION_SYSTEM_CMD, instance.cmdExec, "system/cmd/exec", instance.docCmdExec()).
+		RouteDocument(http.MethodPost, role.PERMISSION_SYSTEM_CMD, instance.cmdComp, "system/cmd/comp", instance.docCmdComp()).
+		RouteDocument(http.MethodGet, role.PERMISSION_SYSTEM_AUDIT, instance.audit, "system/audit", instance.docAudit()).
+		RouteDocument(http.MethodGet, role.PERMISSION_SYSTEM_AUDIT, instance.auditExport, "system/audit/export", instance.docAuditExport()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.metadata, "system/metadata", instance.docMetadata())
 
 	return instance
 }
 
 func (c *ControllerSystem) docLog() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Returns all server-side application logs. Only accessible by admin users.",
+		Description: "Returns all server-side application logs.",
 		Responses: docs.DocResponses{
 			"200": docs.DocJsonPayload[[]record.Record](),
 		},
@@ -51,25 +68,112 @@ func (c *ControllerSystem) docLog() docs.DocRoute {
 }
 
 func (c *ControllerSystem) log(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
-	user := findUser(ctx)
+	store := dependency.Instance().RecordStore
+
+	return result.JsonOk(store.All())
+}
+
+func (c *ControllerSystem) docAudit() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Returns the security audit events, oldest first. The user filter matches both the affected user and the one performing the action.",
+		Query: docs.DocParameters{
+			USERNAME:   USERNAME_DESCRIPTION,
+			QUERY_KIND: QUERY_KIND_DESCRIPTION,
+			QUERY_FROM: QUERY_FROM_DESCRIPTION,
+			QUERY_TO:   QUERY_TO_DESCRIPTION,
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]audit.Event](),
+			"400": docs.DocText(),
+		},
+	}
+}
 
-	sess, res := findSession(user)
+func (c *ControllerSystem) audit(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	filter, res := auditFilter(r)
 	if res != nil {
 		return *res
 	}
 
-	if !sess.HasRole(domain_session.ROLE_ADMIN) {
-		return result.Reject(http.StatusForbidden)
+	return result.JsonOk(c.managerAudit.FindAll(*filter))
+}
+
+func (c *ControllerSystem) docAuditExport() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Exports the security audit events as newline delimited JSON, one event per line, oldest first.",
+		Query:       c.docAudit().Query,
+		Responses: docs.DocResponses{
+			"200": docs.DocText("NDJSON events"),
+			"400": docs.DocText(),
+		},
 	}
+}
 
-	store := dependency.Instance().RecordStore
+func (c *ControllerSystem) auditExport(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	filter, res := auditFilter(r)
+	if res != nil {
+		return *res
+	}
 
-	return result.JsonOk(store.All())
+	events := c.managerAudit.FindAll(*filter)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit.ndjson"`)
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for _, v := range events {
+		if err := encoder.Encode(v); err != nil {
+			log.Errorf("Error writing audit export: %s", err.Error())
+			break
+		}
+	}
+
+	return result.Continue()
+}
+
+func auditFilter(r *http.Request) (*audit.Filter, *result.Result) {
+	query := r.URL.Query()
+
+	filter := audit.Filter{
+		User: query.Get(USERNAME),
+	}
+
+	if raw := query.Get(QUERY_KIND); raw != "" {
+		if !audit.IsKind(raw) {
+			res := result.TextErr(http.StatusBadRequest, "the event type is not valid")
+			return nil, &res
+		}
+		filter.Kind = audit.Kind(raw)
+	}
+
+	bounds := []struct {
+		key   string
+		value *int64
+	}{
+		{QUERY_FROM, &filter.From},
+		{QUERY_TO, &filter.To},
+	}
+
+	for _, v := range bounds {
+		raw := query.Get(v.key)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			res := result.TextErr(http.StatusBadRequest, "the time bounds must be timestamps in milliseconds")
+			return nil, &res
+		}
+		*v.value = value
+	}
+
+	return &filter, nil
 }
 
 func (c *ControllerSystem) docCmdExec() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Executes a system command; requires administrator privileges.",
+		Description: "Executes a system command.",
 		Request:     docs.DocText(CMD_DESCRIPTION),
 		Responses: docs.DocResponses{
 			"200": docs.DocJsonPayload[cmdResult](CMD_SUCCESS_RESPONSE),
@@ -81,17 +185,14 @@ func (c *ControllerSystem) docCmdExec() docs.DocRoute {
 func (c *ControllerSystem) cmdExec(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
 	user := findUser(ctx)
 
-	res := c.hasCmdPrivileges(user)
-	if res != nil {
-		return *res
-	}
-
 	cmd, res := router.InputText(r)
 	if res != nil {
 		return *res
 	}
 
-	cmdRes := command.Exec(user, cmd)
+	recordEvent(c.managerAudit, r, audit.KIND_CMD_EXEC, user, user, cmd)
+
+	cmdRes := render_command.Exec(user, cmd)
 
 	response := cmdResult{
 		Input:  cmdRes.Input,
@@ -103,7 +204,7 @@ func (c *ControllerSystem) cmdExec(w http.ResponseWriter, r *http.Request, ctx *
 
 func (c *ControllerSystem) docCmdComp() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Executes a system command; requires administrator privileges.",
+		Description: "Executes a system command.",
 		Query: docs.DocParameters{
 			CMD_QUERY_POSITION: CMD_QUERY_POSITION_DESCRIPTION,
 		},
@@ -117,11 +218,6 @@ func (c *ControllerSystem) docCmdComp() docs.DocRoute {
 func (c *ControllerSystem) cmdComp(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
 	user := findUser(ctx)
 
-	res := c.hasCmdPrivileges(user)
-	if res != nil {
-		return *res
-	}
-
 	step := -1
 	if raw := r.URL.Query().Get(CMD_QUERY_POSITION); raw != "" {
 		if result, err := strconv.ParseInt(raw, 10, 0); err == nil {
@@ -173,17 +269,3 @@ func (c *ControllerSystem) metadata(w http.ResponseWriter, r *http.Request, ctx
 
 	return result.JsonOk(response)
 }
-
-func (c *ControllerSystem) hasCmdPrivileges(user string) *result.Result {
-	sess, res := findSession(user)
-	if res != nil {
-		return res
-	}
-
-	if !sess.HasRole(domain_session.ROLE_ADMIN) {
-		res := result.TextErr(http.StatusForbidden, "the user does not have privileges to execute cmd actions")
-		return &res
-	}
-
-	return nil
-}
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Rafael24595/go-api-core/src/application/session"
+	"github.com/Rafael24595/go-api-render/src/application/manager"
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-log/log"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+const QUERY_DRY_RUN = "dry_run"
+const QUERY_DRY_RUN_DESCRIPTION = "Checks the archive and reports the changes without storing them"
+
+const QUERY_REMAP = "remap"
+const QUERY_REMAP_DESCRIPTION = "Gives new identifiers to the imported items, to merge them into an account that already holds them"
+
+const TAKEOUT_IMPORT_LIMIT = 256 << 20
+
+type ControllerTakeout struct {
+	router         *SecureRouter
+	managerTakeout *manager.ManagerTakeout
+	managerAudit   *manager.ManagerAudit
+}
+
+func NewControllerTakeout(
+	router *SecureRouter,
+	managerTakeout *manager.ManagerTakeout,
+	managerAudit *manager.ManagerAudit,
+) ControllerTakeout {
+	instance := ControllerTakeout{
+		router:         router,
+		managerTakeout: managerTakeout,
+		managerAudit:   managerAudit,
+	}
+
+	router.
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.takeout, "user/takeout", instance.docTakeout()).
+		RouteDocument(http.MethodPost, role.PERMISSION_NONE, instance.importTakeout, "user/takeout", instance.docImportTakeout()).
+		RouteDocument(http.MethodGet, role.PERMISSION_USERS_MANAGE, instance.takeoutUser, "admin/users/{%s}/takeout", instance.docTakeoutUser()).
+		RouteDocument(http.MethodPost, role.PERMISSION_USERS_MANAGE, instance.importTakeoutUser, "admin/users/{%s}/takeout", instance.docImportTakeoutUser())
+
+	return instance
+}
+
+func (c *ControllerTakeout) docTakeout() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Downloads a ZIP archive with everything the authenticated user owns: collections, default collection requests, historic, contexts, mock end-points and their metrics, token metadata and web data. The manifest.json and SHA256SUMS files list the SHA-256 checksum of every file. Token secrets are never included.",
+		Responses: docs.DocResponses{
+			"200": docs.DocText("ZIP archive"),
+			"500": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerTakeout) takeout(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+	return c.write(w, r, username, username)
+}
+
+func (c *ControllerTakeout) docTakeoutUser() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Downloads the takeout archive of a user, with the same content the user would get.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocText("ZIP archive"),
+			"404": docs.DocText(),
+			"500": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerTakeout) takeoutUser(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := r.PathValue(USERNAME)
+	if _, ok := session.InstanceManagerSession().Find(username); !ok {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	return c.write(w, r, findUser(ctx), username)
+}
+
+func (c *ControllerTakeout) write(w http.ResponseWriter, r *http.Request, actor, username string) result.Result {
+	takeout, err := c.managerTakeout.Collect(username)
+	if err != nil {
+		return result.Err(http.StatusInternalServerError, err)
+	}
+
+	recordEvent(c.managerAudit, r, audit.KIND_TAKEOUT, actor, username, "")
+
+	name := fmt.Sprintf("takeout-%s-%s.zip", username, time.Now().Format("20060102"))
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := takeout.Write(w); err != nil {
+		log.Errorf("Error writing the takeout of %q: %s", username, err.Error())
+	}
+
+	return result.Continue()
+}
+
+func (c *ControllerTakeout) docImportTakeout() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Imports a takeout archive into the authenticated user, recreating its collections, requests, historic, contexts, mock end-points and web data. The manifest and checksums are verified first. Without remapping, the identifiers of the archive are kept and must not exist yet. Nothing is stored if any item conflicts or breaks a namespace schema.",
+		Query: docs.DocParameters{
+			QUERY_DRY_RUN: QUERY_DRY_RUN_DESCRIPTION,
+			QUERY_REMAP:   QUERY_REMAP_DESCRIPTION,
+		},
+		Files: docs.DocParameters{
+			"file": "Takeout archive",
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[manager.TakeoutReport](),
+			"400": docs.DocText(),
+			"409": docs.DocJsonPayload[manager.TakeoutReport](),
+			"422": docs.DocJsonPayload[manager.TakeoutReport](),
+		},
+	}
+}
+
+func (c *ControllerTakeout) importTakeout(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := findUser(ctx)
+	return c.read(w, r, username, username)
+}
+
+func (c *ControllerTakeout) docImportTakeoutUser() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Imports a takeout archive into a user, as the user would do.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Query: docs.DocParameters{
+			QUERY_DRY_RUN: QUERY_DRY_RUN_DESCRIPTION,
+			QUERY_REMAP:   QUERY_REMAP_DESCRIPTION,
+		},
+		Files: docs.DocParameters{
+			"file": "Takeout archive",
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[manager.TakeoutReport](),
+			"400": docs.DocText(),
+			"404": docs.DocText(),
+			"409": docs.DocJsonPayload[manager.TakeoutReport](),
+			"422": docs.DocJsonPayload[manager.TakeoutReport](),
+		},
+	}
+}
+
+func (c *ControllerTakeout) importTakeoutUser(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username := r.PathValue(USERNAME)
+	if _, ok := session.InstanceManagerSession().Find(username); !ok {
+		return result.Reject(http.StatusNotFound)
+	}
+
+	return c.read(w, r, findUser(ctx), username)
+}
+
+func (c *ControllerTakeout) read(w http.ResponseWriter, r *http.Request, actor, username string) result.Result {
+	options := manager.TakeoutImport{}
+
+	query := r.URL.Query()
+	for key, target := range map[string]*bool{QUERY_DRY_RUN: &options.DryRun, QUERY_REMAP: &options.Remap} {
+		raw := query.Get(key)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return result.TextErr(http.StatusBadRequest, fmt.Sprintf("the %s flag must be a boolean", key))
+		}
+		*target = value
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, TAKEOUT_IMPORT_LIMIT)
+
+	err := r.ParseMultipartForm(10 << 20)
+	if err != nil {
+		return result.Err(http.StatusUnprocessableEntity, err)
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return result.Err(http.StatusUnprocessableEntity, err)
+	}
+	defer file.Close()
+
+	takeout, err := manager.ReadTakeout(file, header.Size)
+	if err != nil {
+		return result.Err(http.StatusBadRequest, err)
+	}
+
+	report, err := c.managerTakeout.Import(username, takeout, options)
+	if err != nil {
+		var takeoutError manager.TakeoutError
+		if errors.As(err, &takeoutError) {
+			return result.Err(http.StatusBadRequest, err)
+		}
+		return result.Err(http.StatusInternalServerError, err)
+	}
+
+	if report.Applied {
+		recordEvent(c.managerAudit, r, audit.KIND_TAKEOUT_IMPORT, actor, username, fmt.Sprintf("source=%s remap=%t", report.Source, report.Remap))
+	}
+
+	if len(report.Conflicts) > 0 && !report.DryRun {
+		return jsonStatus(w, http.StatusConflict, report)
+	}
+	if len(report.Violations) > 0 && !report.DryRun {
+		return jsonStatus(w, http.StatusUnprocessableEntity, report)
+	}
+
+	return result.JsonOk(report)
+}
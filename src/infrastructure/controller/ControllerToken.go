@@ -2,9 +2,14 @@ package controller
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/Rafael24595/go-api-core/src/application/manager"
 	"github.com/Rafael24595/go-api-core/src/domain/token"
+	render_manager "github.com/Rafael24595/go-api-render/src/application/manager"
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-api-render/src/domain/scope"
 	"github.com/Rafael24595/go-web/router"
 	"github.com/Rafael24595/go-web/router/docs"
 	"github.com/Rafael24595/go-web/router/result"
@@ -19,32 +24,38 @@ const ID_TOKEN_DESCRIPTION = "Token ID"
 const RAW_TOKEN_DESCRIPTION = "Raw token"
 
 type ControllerToken struct {
-	router       *router.Router
+	router       *SecureRouter
 	managerToken *manager.ManagerToken
+	managerScope *render_manager.ManagerScope
+	managerAudit *render_manager.ManagerAudit
 }
 
 func NewControllerToken(
-	router *router.Router,
+	router *SecureRouter,
 	managerToken *manager.ManagerToken,
+	managerScope *render_manager.ManagerScope,
+	managerAudit *render_manager.ManagerAudit,
 ) ControllerToken {
 	instance := ControllerToken{
 		router:       router,
 		managerToken: managerToken,
+		managerScope: managerScope,
+		managerAudit: managerAudit,
 	}
 
 	router.
-		RouteDocument(http.MethodGet, instance.scopes, "scopes", instance.docScopes()).
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.scopes, "scopes", instance.docScopes()).
 		//
-		RouteDocument(http.MethodGet, instance.find, "token", instance.docFind()).
-		RouteDocument(http.MethodPost, instance.insert, "token", instance.docInsert()).
-		RouteDocument(http.MethodDelete, instance.delete, "token/{%s}", instance.docDelete())
+		RouteDocument(http.MethodGet, role.PERMISSION_TOKENS_MANAGE, instance.find, "token", instance.docFind()).
+		RouteDocument(http.MethodPost, role.PERMISSION_TOKENS_MANAGE, instance.insert, "token", instance.docInsert()).
+		RouteDocument(http.MethodDelete, role.PERMISSION_TOKENS_MANAGE, instance.delete, "token/{%s}", instance.docDelete())
 
 	return instance
 }
 
 func (c *ControllerToken) docScopes() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Returns all token scopes.",
+		Description: "Returns all token scopes. Besides the base scopes, a token may be limited to read or write a resource, such as \"collections:read\" or \"mock:write\".",
 		Responses: docs.DocResponses{
 			"200": docs.DocJsonPayload[[]token.Scope](),
 		},
@@ -52,30 +63,43 @@ func (c *ControllerToken) docScopes() docs.DocRoute {
 }
 
 func (c *ControllerToken) scopes(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
-	return result.JsonOk(token.ListScopes())
+	scopes := token.ListScopes()
+	for _, v := range scope.Scopes() {
+		scopes = append(scopes, token.Scope(v))
+	}
+	return result.JsonOk(scopes)
 }
 
 func (c *ControllerToken) docFind() docs.DocRoute {
 	return docs.DocRoute{
 		Description: "Returns all tokens associated with the authenticated user.",
 		Responses: docs.DocResponses{
-			"200": docs.DocJsonPayload[[]token.LiteToken](),
+			"200": docs.DocJsonPayload[[]responseToken](),
 		},
 	}
 }
 
 func (c *ControllerToken) find(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
 	user := findUser(ctx)
+
 	tokens := c.managerToken.FindAll(user)
-	return result.JsonOk(tokens)
+
+	response := make([]responseToken, len(tokens))
+	for i, v := range tokens {
+		policy, _ := c.managerScope.Find(v.Id)
+		response[i] = makeResponseToken(v, policy)
+	}
+
+	return result.JsonOk(response)
 }
 
 func (c *ControllerToken) docInsert() docs.DocRoute {
 	return docs.DocRoute{
-		Description: "Creates a new token for the authenticated user.",
-		Request:     docs.DocJsonPayload[token.LiteToken](),
+		Description: "Creates a new token for the authenticated user. A token with resource scopes can only access those resources, optionally limited to the given collection and end point IDs. An API token created without resource scopes can only read the resources.",
+		Request:     docs.DocJsonPayload[requestToken](),
 		Responses: docs.DocResponses{
 			"200": docs.DocText(RAW_TOKEN_DESCRIPTION),
+			"422": docs.DocText(),
 		},
 	}
 }
@@ -83,20 +107,58 @@ func (c *ControllerToken) docInsert() docs.DocRoute {
 func (c *ControllerToken) insert(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
 	user := findUser(ctx)
 
-	token, res := router.InputJson[token.LiteToken](r)
+	request, res := router.InputJson[requestToken](r)
 	if res != nil {
 		return *res
 	}
 
-	if token.Name == "" {
+	if request.Name == "" {
 		return result.TextErr(http.StatusUnprocessableEntity, "the token name is not specified")
 	}
 
-	raw, tkn := c.managerToken.Insert(user, &token)
+	// The resource scopes are kept apart from the core ones, which do not
+	// know them. The API scope is still required to authenticate.
+	scopes := make([]string, 0)
+	base := make([]token.Scope, 0, len(request.Scopes))
+	for _, v := range request.Scopes {
+		if scope.IsScope(string(v)) {
+			scopes = append(scopes, string(v))
+		} else {
+			base = append(base, v)
+		}
+	}
+
+	if err := c.managerScope.Validate(scopes, request.Collections, request.EndPoints); err != nil {
+		return result.Err(http.StatusUnprocessableEntity, err)
+	}
+
+	// An API token is never left unscoped, which would give it the full
+	// access of its owner.
+	if len(scopes) == 0 && slices.Contains(base, token.ScopeAPIToken) {
+		for _, v := range scope.Defaults() {
+			scopes = append(scopes, string(v))
+		}
+	}
+
+	if len(scopes) > 0 && !slices.Contains(base, token.ScopeAPIToken) {
+		base = append(base, token.ScopeAPIToken)
+	}
+
+	lite := request.LiteToken
+	lite.Scopes = base
+
+	raw, tkn := c.managerToken.Insert(user, &lite)
 	if tkn == nil {
 		return result.TextErr(http.StatusInternalServerError, "cannot generate the token")
 	}
 
+	if _, err := c.managerScope.Define(user, tkn.Id, scopes, request.Collections, request.EndPoints); err != nil {
+		c.managerToken.DeleteById(user, tkn.Id)
+		return result.Err(http.StatusUnprocessableEntity, err)
+	}
+
+	recordEvent(c.managerAudit, r, audit.KIND_TOKEN_INSERT, user, user, tkn.Id)
+
 	return result.Ok(raw)
 }
 
@@ -125,5 +187,9 @@ func (c *ControllerToken) delete(w http.ResponseWriter, r *http.Request, ctx *ro
 		return result.Reject(http.StatusNotFound)
 	}
 
+	c.managerScope.Delete(id)
+
+	recordEvent(c.managerAudit, r, audit.KIND_TOKEN_DELETE, user, user, id)
+
 	return result.Ok(token)
 }
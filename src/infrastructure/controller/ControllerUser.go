@@ -0,0 +1,482 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Rafael24595/go-api-core/src/application/session"
+	domain_session "github.com/Rafael24595/go-api-core/src/domain/session"
+	"github.com/Rafael24595/go-api-render/src/application/manager"
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+	domain_role "github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-log/log"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+const ROLE = "role"
+const ROLE_DESCRIPTION = "User role"
+
+const QUERY_DISABLED = "disabled"
+const QUERY_DISABLED_DESCRIPTION = "Disabled flag"
+
+type ControllerUser struct {
+	router          *SecureRouter
+	managerDevice   *manager.ManagerDevice
+	managerAccount  *manager.ManagerAccount
+	managerRole     *manager.ManagerRole
+	managerAudit    *manager.ManagerAudit
+	managerPassword *manager.ManagerPassword
+	managerDeletion *manager.ManagerDeletion
+}
+
+func NewControllerUser(
+	router *SecureRouter,
+	managerDevice *manager.ManagerDevice,
+	managerAccount *manager.ManagerAccount,
+	managerRole *manager.ManagerRole,
+	managerAudit *manager.ManagerAudit,
+	managerPassword *manager.ManagerPassword,
+	managerDeletion *manager.ManagerDeletion,
+) ControllerUser {
+	instance := ControllerUser{
+		router:          router,
+		managerDevice:   managerDevice,
+		managerAccount:  managerAccount,
+		managerRole:     managerRole,
+		managerAudit:    managerAudit,
+		managerPassword: managerPassword,
+		managerDeletion: managerDeletion,
+	}
+
+	router.
+		RouteDocument(http.MethodGet, domain_role.PERMISSION_USERS_MANAGE, instance.findAll, "admin/users", instance.docFindAll()).
+		RouteDocument(http.MethodGet, domain_role.PERMISSION_USERS_MANAGE, instance.find, "admin/users/{%s}", instance.docFind()).
+		RouteDocument(http.MethodDelete, domain_role.PERMISSION_USERS_MANAGE, instance.delete, "admin/users/{%s}", instance.docDelete()).
+		RouteDocument(http.MethodDelete, domain_role.PERMISSION_USERS_MANAGE, instance.restore, "admin/users/{%s}/deletion", instance.docRestore()).
+		RouteDocument(http.MethodPut, domain_role.PERMISSION_USERS_MANAGE, instance.reset, "admin/users/{%s}/password", instance.docReset()).
+		RouteDocument(http.MethodPut, domain_role.PERMISSION_USERS_MANAGE, instance.grant, "admin/users/{%s}/roles/{%s}", instance.docGrant()).
+		RouteDocument(http.MethodDelete, domain_role.PERMISSION_USERS_MANAGE, instance.revoke, "admin/users/{%s}/roles/{%s}", instance.docRevoke()).
+		RouteDocument(http.MethodPut, domain_role.PERMISSION_USERS_MANAGE, instance.disable, "admin/users/{%s}/disabled", instance.docDisable()).
+		RouteDocument(http.MethodDelete, domain_role.PERMISSION_USERS_MANAGE, instance.enable, "admin/users/{%s}/disabled", instance.docEnable())
+
+	return instance
+}
+
+func (c *ControllerUser) docFindAll() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Lists the registered users.",
+		Query: docs.DocParameters{
+			USERNAME:       "Part of the user name",
+			ROLE:           ROLE_DESCRIPTION,
+			QUERY_DISABLED: QUERY_DISABLED_DESCRIPTION,
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[[]responseAccount](),
+			"400": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerUser) findAll(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	query := r.URL.Query()
+
+	username := strings.ToLower(query.Get(USERNAME))
+	role := domain_session.Role(query.Get(ROLE))
+
+	var disabled *bool
+	if raw := query.Get(QUERY_DISABLED); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return result.TextErr(http.StatusBadRequest, "the disabled flag must be a boolean")
+		}
+		disabled = &value
+	}
+
+	users := make([]responseAccount, 0)
+	for _, v := range session.InstanceManagerSession().FindAll() {
+		user := c.makeAccount(&v)
+
+		if username != "" && !strings.Contains(strings.ToLower(user.Username), username) {
+			continue
+		}
+		if role != "" && !slices.Contains(user.Roles, role) {
+			continue
+		}
+		if disabled != nil && *disabled != (user.Disabled > 0) {
+			continue
+		}
+
+		users = append(users, user)
+	}
+
+	slices.SortFunc(users, func(a, b responseAccount) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	return result.JsonOk(users)
+}
+
+func (c *ControllerUser) docFind() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Gets a user.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseAccount](),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerUser) find(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	return c.response(r.PathValue(USERNAME))
+}
+
+func (c *ControllerUser) docDelete() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Deletes a user with everything it owns and its sessions, and reports what was removed. With a deletion grace period configured, the account is disabled and its data purged once the period is over.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[manager.DeletionReport](),
+			"202": docs.DocJsonPayload[manager.DeletionReport](),
+			"500": docs.DocJsonPayload[manager.DeletionIncomplete](),
+			"403": docs.DocText(),
+			"404": docs.DocText(),
+			"409": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerUser) delete(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	admin, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	username := r.PathValue(USERNAME)
+	if username == admin.Username {
+		return result.TextErr(http.StatusConflict, "delete your own account from the user endpoint")
+	}
+
+	sessions := session.InstanceManagerSession()
+
+	target, exists := sessions.Find(username)
+	if !exists {
+		return result.Err(http.StatusNotFound, errors.New("user not found"))
+	}
+
+	report, err := c.managerDeletion.Delete(admin, target)
+	incomplete, isIncomplete := err.(*manager.DeletionIncomplete)
+	if err != nil && !isIncomplete {
+		return result.Err(http.StatusForbidden, err)
+	}
+
+	log.Messagef("The user %q has been deleted by %q", username, admin.Username)
+
+	recordEvent(c.managerAudit, r, audit.KIND_USER_DELETE, admin.Username, username, deletionDetail(report, incomplete))
+
+	return deletionResult(w, report, incomplete)
+}
+
+func (c *ControllerUser) docRestore() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Cancels the pending deletion of a user. The user is enabled again unless an admin had disabled it.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseAccount](),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerUser) restore(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	admin, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	username := r.PathValue(USERNAME)
+
+	if !c.managerDeletion.Cancel(username) {
+		return result.Err(http.StatusNotFound, errors.New("no pending deletion for the user"))
+	}
+
+	log.Messagef("The deletion of the user %q has been cancelled by %q", username, admin.Username)
+
+	recordEvent(c.managerAudit, r, audit.KIND_USER_RESTORE, admin.Username, username, "")
+
+	return c.response(username)
+}
+
+func (c *ControllerUser) docReset() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Replaces the password of a user with a temporary one that must be changed on the next login. Closes every session of the user.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseAccountPassword](),
+			"404": docs.DocText(),
+			"409": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerUser) reset(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	admin, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	username := r.PathValue(USERNAME)
+	if username == admin.Username {
+		return result.TextErr(http.StatusConflict, "change your own password from the user endpoint")
+	}
+
+	sessions := session.InstanceManagerSession()
+
+	target, exists := sessions.Find(username)
+	if !exists {
+		return result.Err(http.StatusNotFound, errors.New("user not found"))
+	}
+
+	password, err := randomSecret()
+	if err != nil {
+		return result.Err(http.StatusInternalServerError, err)
+	}
+
+	// The core sessions cannot replace a password without the current one, so
+	// the user is created again keeping its roles. A new user is not verified
+	// until its password is changed. If it cannot be created, the original
+	// session is stored back as it was.
+	original := *target
+	original.Roles = slices.Clone(target.Roles)
+
+	if _, err := sessions.Delete(admin, target); err != nil {
+		return result.Err(http.StatusForbidden, err)
+	}
+
+	if _, err := sessions.Insert(admin, username, password, slices.Clone(original.Roles)); err != nil {
+		sessions.Refresh(&original, original.Refresh)
+		if _, restored := sessions.Find(username); !restored {
+			log.Errorf("The user %q has been removed and could not be restored: %s", username, err.Error())
+		}
+		return result.Err(http.StatusInternalServerError, err)
+	}
+
+	c.managerDevice.RevokeAll(username)
+
+	c.managerPassword.Changed(username, password)
+
+	log.Messagef("The password of the user %q has been reset by %q", username, admin.Username)
+
+	recordEvent(c.managerAudit, r, audit.KIND_PASSWORD_RESET, admin.Username, username, "")
+
+	return result.JsonOk(responseAccountPassword{
+		Username: username,
+		Password: password,
+	})
+}
+
+func (c *ControllerUser) docGrant() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Grants a role to a user.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+			docs.Parameter(ROLE, ROLE_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseAccount](),
+			"404": docs.DocText(),
+			"422": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerUser) grant(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	username, role, res := c.findRole(r)
+	if res != nil {
+		return *res
+	}
+
+	c.managerAccount.Grant(username, role)
+
+	recordEvent(c.managerAudit, r, audit.KIND_ROLE_GRANT, findUser(ctx), username, string(role))
+
+	return c.response(username)
+}
+
+func (c *ControllerUser) docRevoke() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Revokes a role from a user.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+			docs.Parameter(ROLE, ROLE_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseAccount](),
+			"404": docs.DocText(),
+			"409": docs.DocText(),
+			"422": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerUser) revoke(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	admin, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	username, role, res := c.findRole(r)
+	if res != nil {
+		return *res
+	}
+
+	if username == admin.Username && role == domain_session.ROLE_ADMIN {
+		return result.TextErr(http.StatusConflict, "an admin cannot revoke its own admin role")
+	}
+
+	c.managerAccount.Revoke(username, role)
+
+	recordEvent(c.managerAudit, r, audit.KIND_ROLE_REVOKE, admin.Username, username, string(role))
+
+	return c.response(username)
+}
+
+func (c *ControllerUser) docDisable() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Disables a user and closes all its sessions. A disabled user cannot log in or use its API tokens.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseAccount](),
+			"404": docs.DocText(),
+			"409": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerUser) disable(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	admin, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	username := r.PathValue(USERNAME)
+	if username == admin.Username {
+		return result.TextErr(http.StatusConflict, "an admin cannot disable its own account")
+	}
+
+	if _, exists := session.InstanceManagerSession().Find(username); !exists {
+		return result.Err(http.StatusNotFound, errors.New("user not found"))
+	}
+
+	c.managerAccount.Disable(username)
+	c.managerDevice.RevokeAll(username)
+
+	log.Messagef("The user %q has been disabled by %q", username, admin.Username)
+
+	recordEvent(c.managerAudit, r, audit.KIND_USER_DISABLE, admin.Username, username, "")
+
+	return c.response(username)
+}
+
+func (c *ControllerUser) docEnable() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Enables a disabled user.",
+		Parameters: docs.DocOrderParameters{
+			docs.Parameter(USERNAME, USERNAME_DESCRIPTION),
+		},
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[responseAccount](),
+			"404": docs.DocText(),
+		},
+	}
+}
+
+func (c *ControllerUser) enable(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	admin, res := findSession(c.managerAccount, findUser(ctx))
+	if res != nil {
+		return *res
+	}
+
+	username := r.PathValue(USERNAME)
+
+	if _, exists := session.InstanceManagerSession().Find(username); !exists {
+		return result.Err(http.StatusNotFound, errors.New("user not found"))
+	}
+
+	c.managerAccount.Enable(username)
+
+	log.Messagef("The user %q has been enabled by %q", username, admin.Username)
+
+	recordEvent(c.managerAudit, r, audit.KIND_USER_ENABLE, admin.Username, username, "")
+
+	return c.response(username)
+}
+
+func (c *ControllerUser) findRole(r *http.Request) (string, domain_session.Role, *result.Result) {
+	username := r.PathValue(USERNAME)
+
+	name := r.PathValue(ROLE)
+	if name == domain_role.ROLE_USER || !c.managerRole.Exists(name) {
+		res := result.TextErr(http.StatusUnprocessableEntity, "the role does not exist or cannot be assigned")
+		return "", "", &res
+	}
+
+	if _, exists := session.InstanceManagerSession().Find(username); !exists {
+		res := result.Err(http.StatusNotFound, errors.New("user not found"))
+		return "", "", &res
+	}
+
+	return username, domain_session.Role(name), nil
+}
+
+func (c *ControllerUser) response(username string) result.Result {
+	user, ok := c.findAccount(username)
+	if !ok {
+		return result.Err(http.StatusNotFound, errors.New("user not found"))
+	}
+	return result.JsonOk(user)
+}
+
+func (c *ControllerUser) findAccount(username string) (*responseAccount, bool) {
+	user, exists := session.InstanceManagerSession().FindSafe(username)
+	if !exists {
+		return nil, false
+	}
+
+	response := c.makeAccount(user)
+	return &response, true
+}
+
+// makeAccount describes the core user with the account state kept on top of
+// it.
+func (c *ControllerUser) makeAccount(user *domain_session.SessionSafe) responseAccount {
+	response := responseAccount{
+		Username:  user.Username,
+		Timestamp: user.Timestamp,
+		FirstTime: user.Count < 0,
+		Roles:     c.managerAccount.Roles(user.Username, user.Roles),
+	}
+
+	if account, ok := c.managerAccount.Find(user.Username); ok {
+		response.Provider = account.Provider
+		response.Disabled = account.Disabled
+		response.Deletion = account.Deletion
+	}
+
+	return response
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"net/http"
+
+	auth "github.com/Rafael24595/go-api-render/src/commons/auth/Jwt.go"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+type ControllerWellKnown struct {
+	router *SecureRouter
+}
+
+func NewControllerWellKnown(router *SecureRouter) ControllerWellKnown {
+	instance := ControllerWellKnown{
+		router: router,
+	}
+
+	router.
+		RouteDocument(http.MethodGet, role.PERMISSION_NONE, instance.jwks, "/.well-known/jwks.json", instance.docJwks())
+
+	return instance
+}
+
+func (c *ControllerWellKnown) docJwks() docs.DocRoute {
+	return docs.DocRoute{
+		Description: "Returns the public keys used to verify the session tokens as a JSON Web Key Set.",
+		Responses: docs.DocResponses{
+			"200": docs.DocJsonPayload[auth.Jwks](),
+		},
+	}
+}
+
+func (c *ControllerWellKnown) jwks(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	return result.JsonOk(auth.InstanceKeyRing().Jwks())
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-log/log"
+)
+
+const CSRF_COOKIE = "go_api_csrf"
+const CSRF_COOKIE_DESCRIPTION = "Anti-CSRF token, readable by the client"
+
+const CSRF_HEADER = "X-CSRF-Token"
+const CSRF_HEADER_DESCRIPTION = "Copy of the go_api_csrf cookie, required on mutating requests authenticated by cookie"
+
+const ROOT_PATH = "/"
+
+// writeCookie sets a cookie with the attributes configured for the
+// environment. A zero max age defines a session cookie.
+func writeCookie(w http.ResponseWriter, name, value, path string, maxAge int, httpOnly bool) {
+	conf := configuration.Instance().Cookie()
+	http.SetCookie(w, &http.Cookie{
+		Name:     conf.Name(name, path),
+		Value:    value,
+		Path:     path,
+		Domain:   conf.Domain,
+		MaxAge:   maxAge,
+		HttpOnly: httpOnly,
+		Secure:   conf.Secure,
+		SameSite: conf.SameSite,
+	})
+}
+
+func expireCookie(w http.ResponseWriter, name, path string, httpOnly bool) {
+	conf := configuration.Instance().Cookie()
+	http.SetCookie(w, &http.Cookie{
+		Name:     conf.Name(name, path),
+		Value:    "",
+		Path:     path,
+		Domain:   conf.Domain,
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HttpOnly: httpOnly,
+		Secure:   conf.Secure,
+		SameSite: conf.SameSite,
+	})
+}
+
+func readCookie(r *http.Request, name, path string) (*http.Cookie, error) {
+	conf := configuration.Instance().Cookie()
+	return r.Cookie(conf.Name(name, path))
+}
+
+func defineCsrf(w http.ResponseWriter) error {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return err
+	}
+
+	writeCookie(w, CSRF_COOKIE, base64.RawURLEncoding.EncodeToString(buffer), ROOT_PATH, 0, false)
+
+	return nil
+}
+
+func eraseCsrf(w http.ResponseWriter) {
+	expireCookie(w, CSRF_COOKIE, ROOT_PATH, false)
+}
+
+// csrf enforces the double-submit pattern on every request carrying an
+// authentication cookie, whatever route it reaches: a cross-site request
+// carries the cookies but cannot read them to copy the token into the header.
+// Requests with an Authorization or X-API-Key header are not checked, as a
+// cross-site request cannot set them and the cookies are then ignored. Safe
+// requests are issued the token when they lack it, so sessions started before
+// it existed can keep mutating.
+func csrf(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := checkCsrf(w, r); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func checkCsrf(w http.ResponseWriter, r *http.Request) error {
+	if !configuration.Instance().Cookie().Csrf || !hasAuthCookie(r) {
+		return nil
+	}
+
+	if r.Header.Get(AUTH_HEADER) != "" || r.Header.Get(API_KEY_HEADER) != "" {
+		return nil
+	}
+
+	cookie, err := readCookie(r, CSRF_COOKIE, ROOT_PATH)
+	missing := err != nil || cookie.Value == ""
+
+	if isSafeMethod(r.Method) {
+		if missing {
+			if err := defineCsrf(w); err != nil {
+				log.Error(err)
+			}
+		}
+		return nil
+	}
+
+	if missing {
+		return errors.New("the CSRF token is missing")
+	}
+
+	header := r.Header.Get(CSRF_HEADER)
+	if subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) != 1 {
+		return errors.New("the CSRF token does not match")
+	}
+
+	return nil
+}
+
+func hasAuthCookie(r *http.Request) bool {
+	for _, v := range []struct{ name, path string }{
+		{AUTH_COOKIE, ROOT_PATH},
+		{AUTH_TOKEN, ROOT_PATH},
+		{REFRESH_COOKIE, REFRESH_PATH},
+	} {
+		if cookie, err := readCookie(r, v.name, v.path); err == nil && cookie.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
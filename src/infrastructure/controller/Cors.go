@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+)
+
+const CORS_METHODS = "GET, HEAD, POST, PUT, PATCH, DELETE"
+const CORS_HEADERS = "Authorization, Content-Type, If-Match, X-API-Key, X-CSRF-Token"
+const CORS_EXPOSED = "ETag, Retry-After"
+const CORS_MAX_AGE = "600"
+
+// cors answers the preflight requests and adds the CORS headers to the
+// responses of the configured origins. Any other origin gets no CORS header,
+// so the browser keeps it from reading the responses.
+func cors(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+
+		if !configuration.Instance().Cors().Allows(origin) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Set("Access-Control-Allow-Credentials", "true")
+		header.Set("Access-Control-Expose-Headers", CORS_EXPOSED)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", CORS_METHODS)
+			header.Set("Access-Control-Allow-Headers", CORS_HEADERS)
+			header.Set("Access-Control-Max-Age", CORS_MAX_AGE)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+)
+
+const ETAG_HEADER = "ETag"
+
+const IF_MATCH_HEADER = "If-Match"
+
+func writeETag(w http.ResponseWriter, etag string) {
+	w.Header().Set(ETAG_HEADER, etag)
+}
+
+// matchesETag reports whether the If-Match precondition of the request holds
+// for the current version. Requests without the header are unconditional.
+func matchesETag(r *http.Request, etag string) bool {
+	header := strings.TrimSpace(r.Header.Get(IF_MATCH_HEADER))
+	if header == "" || header == "*" {
+		return true
+	}
+
+	for _, v := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(v), "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
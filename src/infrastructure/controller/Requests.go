@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"encoding/json"
+
 	"github.com/Rafael24595/go-api-core/src/application/command"
 	"github.com/Rafael24595/go-api-core/src/application/manager"
 	"github.com/Rafael24595/go-api-core/src/domain"
+	"github.com/Rafael24595/go-api-core/src/domain/token"
 	"github.com/Rafael24595/go-api-core/src/infrastructure/dto"
 )
 
@@ -27,8 +30,64 @@ type requestInsertAction struct {
 }
 
 type requestLogin struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	RememberMe bool   `json:"remember_me"`
+}
+
+type requestLoginChallenge struct {
+	Challenge  string `json:"challenge"`
+	Code       string `json:"code"`
+	RememberMe bool   `json:"remember_me"`
+}
+
+type requestFactorCode struct {
+	Code string `json:"code"`
+}
+
+type requestFactorPolicy struct {
+	Admins bool `json:"admins"`
+}
+
+type requestRole struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type requestNamespace struct {
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	Quota       int64           `json:"quota"`
+}
+
+type requestToken struct {
+	token.LiteToken
+	Collections []string `json:"collections"`
+	EndPoints   []string `json:"endpoints"`
+}
+
+// requestWebDataPatch is a JSON Merge Patch of the web data, where only the
+// data member can be changed. A null data removes every entry and a null
+// entry removes the key.
+type requestWebDataPatch struct {
+	Data json.RawMessage `json:"data"`
+}
+
+func (r requestWebDataPatch) entries() (map[string]*string, bool, error) {
+	entries := make(map[string]*string)
+	if len(r.Data) == 0 {
+		return entries, false, nil
+	}
+
+	if string(r.Data) == "null" {
+		return entries, true, nil
+	}
+
+	if err := json.Unmarshal(r.Data, &entries); err != nil {
+		return nil, false, err
+	}
+
+	return entries, false, nil
 }
 
 type requestVerify struct {
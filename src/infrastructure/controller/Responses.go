@@ -1,12 +1,18 @@
 package controller
 
 import (
+	"slices"
+
 	core_configuration "github.com/Rafael24595/go-api-core/src/commons/configuration"
 
 	"github.com/Rafael24595/go-api-core/src/commons/local"
 	"github.com/Rafael24595/go-api-core/src/domain/session"
+	"github.com/Rafael24595/go-api-core/src/domain/token"
 	"github.com/Rafael24595/go-api-core/src/infrastructure/dto"
+	"github.com/Rafael24595/go-api-render/src/application/manager"
 	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-api-render/src/domain/device"
+	"github.com/Rafael24595/go-api-render/src/domain/scope"
 	"github.com/Rafael24595/go-web/router/docs"
 )
 
@@ -31,9 +37,157 @@ type responseUserData struct {
 	Username  string         `json:"username"`
 	Timestamp int64          `json:"timestamp"`
 	FirstTime bool           `json:"first_time"`
+	Expired   bool           `json:"password_expired"`
 	Roles     []session.Role `json:"roles"`
 }
 
+type responseFieldErrors struct {
+	Errors []responseFieldError `json:"errors"`
+}
+
+type responseFieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func makeResponseFieldErrors(field string, violations []manager.PasswordViolation) responseFieldErrors {
+	errors := make([]responseFieldError, len(violations))
+	for i, v := range violations {
+		errors[i] = responseFieldError{
+			Field:   field,
+			Code:    v.Code,
+			Message: v.Message,
+		}
+	}
+	return responseFieldErrors{
+		Errors: errors,
+	}
+}
+
+// makeResponseNamespaceErrors locates the violations of the web data, the
+// flat data as "data" and the namespaces as "namespaces.<name><path>".
+func makeResponseNamespaceErrors(violations []manager.NamespaceViolation) responseFieldErrors {
+	errors := make([]responseFieldError, len(violations))
+	for i, v := range violations {
+		field := "data"
+		if v.Namespace != "" {
+			field = "namespaces." + v.Namespace
+			if v.Path != "" && v.Path != "/" {
+				field += v.Path
+			}
+		}
+		errors[i] = responseFieldError{
+			Field:   field,
+			Code:    v.Code,
+			Message: v.Message,
+		}
+	}
+	return responseFieldErrors{
+		Errors: errors,
+	}
+}
+
+type responseAccount struct {
+	Username  string         `json:"username"`
+	Provider  string         `json:"provider"`
+	Timestamp int64          `json:"timestamp"`
+	FirstTime bool           `json:"first_time"`
+	Roles     []session.Role `json:"roles"`
+	Disabled  int64          `json:"disabled"`
+	Deletion  int64          `json:"deletion"`
+}
+
+type responseAccountPassword struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type responseSession struct {
+	Id        string `json:"id"`
+	Agent     string `json:"agent"`
+	Address   string `json:"address"`
+	Timestamp int64  `json:"timestamp"`
+	LastSeen  int64  `json:"last_seen"`
+	Revoked   int64  `json:"revoked"`
+	Current   bool   `json:"current"`
+}
+
+func makeResponseSession(current string, device device.Device) responseSession {
+	return responseSession{
+		Id:        device.Id,
+		Agent:     device.Agent,
+		Address:   device.Address,
+		Timestamp: device.Timestamp,
+		LastSeen:  device.LastSeen,
+		Revoked:   device.Revoked,
+		Current:   device.Id == current,
+	}
+}
+
+func makeResponseSessions(current string, devices ...device.Device) []responseSession {
+	sessions := make([]responseSession, len(devices))
+	for i, v := range devices {
+		sessions[i] = makeResponseSession(current, v)
+	}
+	return sessions
+}
+
+type responseToken struct {
+	token.LiteToken
+	Scopes      []token.Scope `json:"scopes"`
+	Collections []string      `json:"collections"`
+	EndPoints   []string      `json:"endpoints"`
+}
+
+func makeResponseToken(lite token.LiteToken, policy *scope.Policy) responseToken {
+	response := responseToken{
+		LiteToken:   lite,
+		Scopes:      slices.Clone(lite.Scopes),
+		Collections: make([]string, 0),
+		EndPoints:   make([]string, 0),
+	}
+
+	if policy == nil {
+		return response
+	}
+
+	for _, v := range policy.Scopes {
+		response.Scopes = append(response.Scopes, token.Scope(v))
+	}
+	response.Collections = policy.Collections
+	response.EndPoints = policy.EndPoints
+
+	return response
+}
+
+type responseLoginChallenge struct {
+	Challenge string `json:"challenge"`
+	Method    string `json:"method"`
+	Expires   int64  `json:"expires"`
+}
+
+type responseFactor struct {
+	Enabled  bool  `json:"enabled"`
+	Pending  bool  `json:"pending"`
+	Required bool  `json:"required"`
+	Recovery int   `json:"recovery"`
+	Since    int64 `json:"since"`
+}
+
+type responseFactorEnroll struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+type responseFactorRecovery struct {
+	Codes []string `json:"codes"`
+}
+
+type responseFactorPolicy struct {
+	Admins bool `json:"admins"`
+}
+
 type responseSystemMetadata struct {
 	SessionId     string                  `json:"session_id"`
 	SessionTime   int64                   `json:"session_time"`
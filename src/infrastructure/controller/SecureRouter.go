@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	render_manager "github.com/Rafael24595/go-api-render/src/application/manager"
+	"github.com/Rafael24595/go-api-render/src/domain/audit"
+	"github.com/Rafael24595/go-api-render/src/domain/role"
+	"github.com/Rafael24595/go-api-render/src/domain/scope"
+	"github.com/Rafael24595/go-web/router"
+	"github.com/Rafael24595/go-web/router/docs"
+	"github.com/Rafael24595/go-web/router/result"
+)
+
+// The resource reached by a route is found from its path, the first matching
+// prefix wins.
+var resourceRoutes = []struct {
+	prefix   string
+	resource scope.Resource
+}{
+	{"mock/", scope.RESOURCE_MOCK},
+	{"bridge/mock/", scope.RESOURCE_MOCK},
+	{"sort/mock/", scope.RESOURCE_MOCK},
+	{"export/mock/", scope.RESOURCE_MOCK},
+	{"import/mock/", scope.RESOURCE_MOCK},
+	{"curl/endpoint/", scope.RESOURCE_MOCK},
+	{"historic", scope.RESOURCE_HISTORIC},
+	{"collection", scope.RESOURCE_COLLECTIONS},
+	{"request", scope.RESOURCE_COLLECTIONS},
+	{"context", scope.RESOURCE_COLLECTIONS},
+	{"action", scope.RESOURCE_COLLECTIONS},
+	{"import/", scope.RESOURCE_COLLECTIONS},
+	{"sort/", scope.RESOURCE_COLLECTIONS},
+	{"export/", scope.RESOURCE_COLLECTIONS},
+	{"curl/", scope.RESOURCE_COLLECTIONS},
+}
+
+// SecureRouter registers the routes of the controllers, requiring each one
+// to declare the permission it needs. The permission is checked against the
+// user put in the context by the authentication groups, so a route requiring
+// one must be covered by a group. PERMISSION_NONE is only for the routes open
+// to anyone or to every authenticated user.
+//
+// Requests authenticated with a scoped API token are also limited to the
+// resource of the route, read or written depending on the method. A token
+// without scopes, created before they existed, keeps the full access of its
+// owner; its first use since the start is audited so it can be replaced.
+type SecureRouter struct {
+	router         *router.Router
+	managerRole    *render_manager.ManagerRole
+	managerAccount *render_manager.ManagerAccount
+	managerScope   *render_manager.ManagerScope
+	managerAudit   *render_manager.ManagerAudit
+	unscoped       sync.Map
+}
+
+func NewSecureRouter(
+	router *router.Router,
+	managerRole *render_manager.ManagerRole,
+	managerAccount *render_manager.ManagerAccount,
+	managerScope *render_manager.ManagerScope,
+	managerAudit *render_manager.ManagerAudit,
+) *SecureRouter {
+	return &SecureRouter{
+		router:         router,
+		managerRole:    managerRole,
+		managerAccount: managerAccount,
+		managerScope:   managerScope,
+		managerAudit:   managerAudit,
+	}
+}
+
+func (s *SecureRouter) RouteDocument(method string, permission role.Permission, handler router.RequestHandler, path string, doc docs.DocRoute) *SecureRouter {
+	if permission != role.PERMISSION_NONE {
+		handler = s.permit(permission, handler)
+		doc = documentPermission(permission, doc)
+	}
+
+	handler = s.scope(method, path, handler)
+
+	s.router.RouteDocument(method, handler, path, doc)
+
+	return s
+}
+
+func (s *SecureRouter) ViewerSources() []docs.DocViewerSources {
+	return s.router.ViewerSources()
+}
+
+func (s *SecureRouter) permit(permission role.Permission, handler router.RequestHandler) router.RequestHandler {
+	return func(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+		session, res := findSession(s.managerAccount, findUser(ctx))
+		if res != nil {
+			return *res
+		}
+
+		if !s.managerRole.IsGranted(session.Roles, permission) {
+			return result.TextErr(http.StatusForbidden, fmt.Sprintf("the %q permission is required", permission))
+		}
+
+		return handler(w, r, ctx)
+	}
+}
+
+func (s *SecureRouter) scope(method, path string, handler router.RequestHandler) router.RequestHandler {
+	resource, reachable := routeResource(path)
+	access := routeAccess(method, path)
+
+	return func(w http.ResponseWriter, r *http.Request, ctx *router.Context) result.Result {
+		id := findApiTokenId(ctx)
+		if id == "" {
+			return handler(w, r, ctx)
+		}
+
+		policy, ok := s.managerScope.Find(id)
+		if !ok {
+			if _, seen := s.unscoped.LoadOrStore(id, true); !seen {
+				user := findUser(ctx)
+				recordEvent(s.managerAudit, r, audit.KIND_TOKEN_UNSCOPED, user, user, id)
+			}
+			return handler(w, r, ctx)
+		}
+
+		if !reachable {
+			return result.TextErr(http.StatusForbidden, "the route cannot be accessed with a scoped token")
+		}
+
+		if !policy.Allows(resource, access) {
+			return result.TextErr(http.StatusForbidden, fmt.Sprintf("the token does not have the %q scope", scope.Of(resource, access)))
+		}
+
+		if restricted := policy.Restricts(resource); len(restricted) > 0 {
+			if !slices.Contains(restricted, routeResourceId(r, resource)) {
+				return result.TextErr(http.StatusForbidden, fmt.Sprintf("the token is restricted to other %s", resource))
+			}
+		}
+
+		return handler(w, r, ctx)
+	}
+}
+
+func routeResource(path string) (scope.Resource, bool) {
+	for _, v := range resourceRoutes {
+		if strings.HasPrefix(path, v.prefix) {
+			return v.resource, true
+		}
+	}
+	return "", false
+}
+
+func routeAccess(method, path string) scope.Access {
+	if isSafeMethod(method) || strings.HasPrefix(path, "export/") || path == "curl/request" {
+		return scope.ACCESS_READ
+	}
+	return scope.ACCESS_WRITE
+}
+
+func routeResourceId(r *http.Request, resource scope.Resource) string {
+	switch resource {
+	case scope.RESOURCE_COLLECTIONS:
+		if id := r.PathValue(ID_COLLECTION); id != "" {
+			return id
+		}
+		return r.URL.Query().Get(ID_COLLECTION)
+	case scope.RESOURCE_MOCK:
+		return r.PathValue(ID_END_POINT)
+	default:
+		return ""
+	}
+}
+
+func documentPermission(permission role.Permission, doc docs.DocRoute) docs.DocRoute {
+	doc.Description = fmt.Sprintf("%s Requires the %q permission.", doc.Description, permission)
+
+	responses := make(docs.DocResponses, len(doc.Responses)+1)
+	for k, v := range doc.Responses {
+		responses[k] = v
+	}
+	if _, ok := responses["403"]; !ok {
+		responses["403"] = docs.DocText(fmt.Sprintf("Missing the %q permission", permission))
+	}
+	doc.Responses = responses
+
+	return doc
+}
@@ -1,5 +1,20 @@
 package repository
 
 const (
-	CSVT_FILE_PATH_WEB_DATA string = "./db/table_web.csvt"
+	CSVT_FILE_PATH_WEB_DATA  string = "./db/table_web.csvt"
+	CSVT_FILE_PATH_DEVICE    string = "./db/table_device.csvt"
+	CSVT_FILE_PATH_FACTOR    string = "./db/table_factor.csvt"
+	CSVT_FILE_PATH_SETTING   string = "./db/table_setting.csvt"
+	CSVT_FILE_PATH_ACCOUNT   string = "./db/table_account.csvt"
+	CSVT_FILE_PATH_ROLE      string = "./db/table_role.csvt"
+	CSVT_FILE_PATH_SCOPE     string = "./db/table_scope.csvt"
+	CSVT_FILE_PATH_AUDIT     string = "./db/table_audit.csvt"
+	CSVT_FILE_PATH_NAMESPACE string = "./db/table_namespace.csvt"
+	CSVT_FILE_PATH_REVISION  string = "./db/table_web_revision.csvt"
+)
+
+const (
+	JOURNAL_FILE_PATH_WEB_DATA string = "./db/journal_web.ndjson"
+	JOURNAL_FILE_PATH_AUDIT    string = "./db/journal_audit.ndjson"
+	BOLT_FILE_PATH_WEB_DATA    string = "./db/web.db"
 )
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-log/log"
+)
+
+type journalOperation string
+
+const (
+	journalPut    journalOperation = "put"
+	journalDelete journalOperation = "delete"
+)
+
+type journalRecord[T core_repository.IStructure] struct {
+	Operation journalOperation `json:"op"`
+	Id        string           `json:"id"`
+	Item      *T               `json:"item,omitempty"`
+}
+
+// IJournalFile is a file manager that can persist single changes, so a
+// repository does not need to hand it every item on every write.
+type IJournalFile[T core_repository.IStructure] interface {
+	core_repository.IFileManager[T]
+	Put(items ...T) error
+	Remove(ids ...string) error
+	Close() error
+}
+
+// ManagerJournalFile persists the changes as records appended to a journal,
+// instead of rewriting every item on every write. Once the journal holds the
+// configured number of records it is compacted into the base file, the CSVT
+// file at basePath read by base. Reading replays the journal over the base
+// file, ignoring a last record left incomplete by a crash; any other broken
+// record is reported.
+type ManagerJournalFile[T core_repository.IStructure] struct {
+	mu       sync.Mutex
+	path     string
+	basePath string
+	base     core_repository.IFileManager[T]
+	compact  int
+	records  int
+	file     *os.File
+}
+
+func NewManagerJournalFile[T core_repository.IStructure](path, basePath string, base core_repository.IFileManager[T], compact int) *ManagerJournalFile[T] {
+	return &ManagerJournalFile[T]{
+		path:     path,
+		basePath: basePath,
+		base:     base,
+		compact:  compact,
+	}
+}
+
+func (m *ManagerJournalFile[T]) Read() (map[string]T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	items, records, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+
+	m.records = records
+
+	return items, nil
+}
+
+// Write replaces every item: they are written to the base file and the
+// journal is emptied. Single changes go through Put and Remove.
+func (m *ManagerJournalFile[T]) Write(items []T) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.compactJournal(items)
+}
+
+// Put appends a record for every given item, and syncs the journal before
+// returning.
+func (m *ManagerJournalFile[T]) Put(items ...T) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	buffer := new(bytes.Buffer)
+	for i := range items {
+		record := journalRecord[T]{Operation: journalPut, Id: items[i].PersistenceId(), Item: &items[i]}
+		if err := appendRecord(buffer, record); err != nil {
+			return err
+		}
+	}
+
+	return m.append(buffer.Bytes(), len(items))
+}
+
+// Remove appends a deletion record for every given identifier, and syncs the
+// journal before returning.
+func (m *ManagerJournalFile[T]) Remove(ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	buffer := new(bytes.Buffer)
+	for _, v := range ids {
+		if err := appendRecord(buffer, journalRecord[T]{Operation: journalDelete, Id: v}); err != nil {
+			return err
+		}
+	}
+
+	return m.append(buffer.Bytes(), len(ids))
+}
+
+// Close releases the journal file.
+func (m *ManagerJournalFile[T]) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.file == nil {
+		return nil
+	}
+
+	err := m.file.Close()
+	m.file = nil
+
+	return err
+}
+
+func (m *ManagerJournalFile[T]) load() (map[string]T, int, error) {
+	items, err := m.base.Read()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if items == nil {
+		items = make(map[string]T)
+	}
+
+	records, err := m.replay(items)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, records, nil
+}
+
+// compactJournal replaces the base file with every item and empties the
+// journal. A crash at any point leaves either the old or the new base file,
+// and replaying the journal over any of them leads to the same items.
+func (m *ManagerJournalFile[T]) compactJournal(items []T) error {
+	if err := m.replaceBase(items); err != nil {
+		return err
+	}
+
+	if err := m.truncate(0); err != nil {
+		return err
+	}
+
+	m.records = 0
+
+	return nil
+}
+
+// replaceBase writes the items to a temporary file, synced before it is
+// renamed over the base file, so the base file is never left half written.
+func (m *ManagerJournalFile[T]) replaceBase(items []T) error {
+	directory := filepath.Dir(m.basePath)
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+
+	temporary := m.basePath + ".tmp"
+	if err := core_repository.NewManagerCsvtFile[T](temporary).Write(items); err != nil {
+		return err
+	}
+
+	if err := syncPath(temporary); err != nil {
+		return err
+	}
+
+	if err := os.Rename(temporary, m.basePath); err != nil {
+		return err
+	}
+
+	return syncPath(directory)
+}
+
+// append writes the records to the journal, kept open between writes. The
+// journal is compacted once it reaches the configured size; the items are
+// then read back from the base file and the journal, which only happens once
+// every so many records.
+func (m *ManagerJournalFile[T]) append(records []byte, count int) error {
+	if count == 0 {
+		return nil
+	}
+
+	if err := m.open(); err != nil {
+		return err
+	}
+
+	if _, err := m.file.Write(records); err != nil {
+		return err
+	}
+
+	if err := m.file.Sync(); err != nil {
+		return err
+	}
+
+	m.records += count
+	if m.records < m.compact {
+		return nil
+	}
+
+	items, _, err := m.load()
+	if err != nil {
+		return err
+	}
+
+	values := make([]T, 0, len(items))
+	for _, v := range items {
+		values = append(values, v)
+	}
+
+	return m.compactJournal(values)
+}
+
+func (m *ManagerJournalFile[T]) open() error {
+	if m.file != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	m.file = file
+
+	return nil
+}
+
+func (m *ManagerJournalFile[T]) truncate(size int64) error {
+	if m.file != nil {
+		return m.file.Truncate(size)
+	}
+
+	if err := os.Truncate(m.path, size); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ManagerJournalFile[T]) replay(items map[string]T) (int, error) {
+	file, err := os.Open(m.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	var offset int64
+	count := 0
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				return count, m.discard(offset)
+			}
+			return count, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		var record journalRecord[T]
+		if err := json.Unmarshal(line, &record); err != nil {
+			if _, peek := reader.Peek(1); peek == io.EOF {
+				return count, m.discard(offset)
+			}
+			return 0, fmt.Errorf("the journal %q has a broken record at offset %d: %s", m.path, offset, err.Error())
+		}
+
+		switch record.Operation {
+		case journalPut:
+			if record.Item != nil {
+				items[record.Id] = *record.Item
+			}
+		case journalDelete:
+			delete(items, record.Id)
+		}
+
+		offset += int64(len(line))
+		count++
+	}
+}
+
+// discard drops the last record of the journal, which a crash cut short.
+func (m *ManagerJournalFile[T]) discard(offset int64) error {
+	log.Warningf("The journal %q ends with an incomplete record; it is discarded.", m.path)
+	return m.truncate(offset)
+}
+
+func syncPath(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return file.Sync()
+}
+
+func appendRecord[T core_repository.IStructure](buffer *bytes.Buffer, record journalRecord[T]) error {
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	buffer.Write(encoded)
+	buffer.WriteByte('\n')
+	return nil
+}
@@ -0,0 +1,381 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	core_system "github.com/Rafael24595/go-api-core/src/commons/system"
+	"github.com/Rafael24595/go-api-core/src/commons/system/topic"
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-collections/collection"
+	"github.com/Rafael24595/go-log/log"
+	"github.com/google/uuid"
+)
+
+// writeDelay is how long the writer waits for more changes before flushing,
+// so a burst of changes is written to the file once.
+const writeDelay = 250 * time.Millisecond
+
+// RepositoryMemory keeps the items in memory and writes them to the file from
+// a single background writer, so the writes never overlap nor land out of
+// order. When the file is a journal, every change is instead appended to it
+// before it is applied, so no change is acknowledged before it is persisted.
+// The repositories of each domain build on it, adding their lookups and how
+// an item is stamped when it is stored.
+type RepositoryMemory[T core_repository.IStructure] struct {
+	once       sync.Once
+	onceClose  sync.Once
+	muMemory   sync.RWMutex
+	muFile     sync.Mutex
+	name       string
+	topic      topic.TopicAction
+	collection collection.IDictionary[string, T]
+	file       core_repository.IFileManager[T]
+	journal    IJournalFile[T]
+	key        func(item T) string
+	index      map[string]string
+	dirty      chan struct{}
+	stop       chan struct{}
+	done       chan error
+	close      chan bool
+}
+
+func NewRepositoryMemory[T core_repository.IStructure](name string, topic topic.TopicAction, impl collection.IDictionary[string, T], file core_repository.IFileManager[T]) (*RepositoryMemory[T], error) {
+	items, err := file.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	instance := &RepositoryMemory[T]{
+		name:       name,
+		topic:      topic,
+		collection: impl.Merge(collection.DictionaryFromMap(items)),
+		file:       file,
+		dirty:      make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		done:       make(chan error, 1),
+		close:      make(chan bool),
+	}
+
+	if journal, ok := file.(IJournalFile[T]); ok {
+		instance.journal = journal
+	}
+
+	go instance.watch()
+	go instance.writer()
+
+	return instance, nil
+}
+
+func (r *RepositoryMemory[T]) watch() {
+	r.once.Do(func() {
+		conf := configuration.Instance()
+		if !conf.Snapshot().Enable {
+			return
+		}
+
+		hub := make(chan core_system.SystemEvent, 1)
+		defer close(hub)
+
+		topics := []topic.TopicAction{
+			r.topic,
+		}
+
+		conf.EventHub.Subcribe(core_repository.RepositoryListener, hub, topics...)
+		defer conf.EventHub.Unsubcribe(core_repository.RepositoryListener, topics...)
+
+		for {
+			select {
+			case <-r.close:
+				log.Customf(core_repository.RepositoryCategory, "Watcher stopped: local close signal received.")
+				return
+			case <-hub:
+				if err := r.read(); err != nil {
+					log.Custome(core_repository.RepositoryCategory, err)
+					return
+				}
+				log.Customf(core_repository.RepositoryCategory, "The repository %q has been reloaded.", r.name)
+			case <-conf.Signal.Done():
+				log.Customf(core_repository.RepositoryCategory, "Watcher stopped: global shutdown signal received.")
+				return
+			}
+		}
+	})
+}
+
+func (r *RepositoryMemory[T]) read() error {
+	items, err := r.file.Read()
+	if err != nil {
+		return err
+	}
+
+	r.muMemory.Lock()
+	defer r.muMemory.Unlock()
+
+	r.collection = collection.DictionaryFromMap(items)
+	r.reindex()
+
+	return nil
+}
+
+// IndexBy keeps the items indexed by the given key, so FindIndexed finds them
+// without a scan. Each key is expected to belong to a single item.
+func (r *RepositoryMemory[T]) IndexBy(key func(item T) string) *RepositoryMemory[T] {
+	r.muMemory.Lock()
+	defer r.muMemory.Unlock()
+
+	r.key = key
+	r.reindex()
+
+	return r
+}
+
+// reindex rebuilds the index. The memory lock must be held.
+func (r *RepositoryMemory[T]) reindex() {
+	if r.key == nil {
+		return
+	}
+
+	r.index = make(map[string]string)
+	for _, v := range r.collection.Values() {
+		r.index[r.key(v)] = v.PersistenceId()
+	}
+}
+
+// FindIndexed returns the item with the given key of the index.
+func (r *RepositoryMemory[T]) FindIndexed(key string) (*T, bool) {
+	r.muMemory.RLock()
+	defer r.muMemory.RUnlock()
+
+	id, ok := r.index[key]
+	if !ok {
+		var item T
+		return &item, false
+	}
+
+	item, ok := r.collection.Get(id)
+	return &item, ok
+}
+
+func (r *RepositoryMemory[T]) FindAll() []T {
+	r.muMemory.RLock()
+	defer r.muMemory.RUnlock()
+	return r.collection.Values()
+}
+
+func (r *RepositoryMemory[T]) Find(id string) (*T, bool) {
+	r.muMemory.RLock()
+	defer r.muMemory.RUnlock()
+	item, ok := r.collection.Get(id)
+	return &item, ok
+}
+
+// FindOne returns the first item matching the predicate.
+func (r *RepositoryMemory[T]) FindOne(predicate func(item T) bool) (*T, bool) {
+	r.muMemory.RLock()
+	defer r.muMemory.RUnlock()
+	item, ok := r.collection.FindOne(func(_ string, item T) bool {
+		return predicate(item)
+	})
+	return &item, ok
+}
+
+// FindMany returns every item matching the predicate.
+func (r *RepositoryMemory[T]) FindMany(predicate func(item T) bool) []T {
+	r.muMemory.RLock()
+	defer r.muMemory.RUnlock()
+	result := make([]T, 0)
+	for _, v := range r.collection.Values() {
+		if predicate(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Store stamps the item and stores it under its persistence identifier. The
+// stamp receives a key no other item uses, for the items still without one.
+// A failed journal write is logged; use Save to handle it.
+func (r *RepositoryMemory[T]) Store(item *T, stamp func(item *T, key string)) *T {
+	result, err := r.Save(item, stamp)
+	if err != nil {
+		log.Error(err)
+		return item
+	}
+	return result
+}
+
+// Save is Store reporting the error of the journal, in which case the item is
+// not stored.
+func (r *RepositoryMemory[T]) Save(item *T, stamp func(item *T, key string)) (*T, error) {
+	r.muMemory.Lock()
+	defer r.muMemory.Unlock()
+
+	key := uuid.New().String()
+	for r.collection.Exists(key) {
+		key = uuid.New().String()
+	}
+
+	stamp(item, key)
+
+	id := (*item).PersistenceId()
+
+	if r.journal != nil {
+		if err := r.journal.Put(*item); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.key != nil {
+		if previous, ok := r.collection.Get(id); ok && r.index[r.key(previous)] == id {
+			delete(r.index, r.key(previous))
+		}
+		r.index[r.key(*item)] = id
+	}
+
+	r.collection.Put(id, *item)
+	r.schedule()
+
+	return item, nil
+}
+
+// Remove deletes the item with the given identifier. The zero item is
+// returned when it does not exist. A failed journal write is logged; use
+// Discard to handle it.
+func (r *RepositoryMemory[T]) Remove(id string) (*T, bool) {
+	cursor, ok, err := r.Discard(id)
+	if err != nil {
+		log.Error(err)
+	}
+	return cursor, ok
+}
+
+// Discard is Remove reporting the error of the journal, in which case the
+// item is kept.
+func (r *RepositoryMemory[T]) Discard(id string) (*T, bool, error) {
+	r.muMemory.Lock()
+	defer r.muMemory.Unlock()
+
+	if r.journal != nil && r.collection.Exists(id) {
+		if err := r.journal.Remove(id); err != nil {
+			var item T
+			return &item, false, err
+		}
+	}
+
+	cursor, ok := r.collection.Remove(id)
+	if ok {
+		r.unindex(cursor)
+		r.schedule()
+	}
+
+	return &cursor, ok, nil
+}
+
+// RemoveMany deletes the items with the given identifiers and returns the
+// ones that existed.
+func (r *RepositoryMemory[T]) RemoveMany(ids ...string) []T {
+	r.muMemory.Lock()
+	defer r.muMemory.Unlock()
+
+	existing := make([]string, 0, len(ids))
+	for _, v := range ids {
+		if r.collection.Exists(v) {
+			existing = append(existing, v)
+		}
+	}
+
+	if r.journal != nil && len(existing) > 0 {
+		if err := r.journal.Remove(existing...); err != nil {
+			log.Error(err)
+			return make([]T, 0)
+		}
+	}
+
+	result := make([]T, 0, len(existing))
+	for _, v := range existing {
+		if cursor, ok := r.collection.Remove(v); ok {
+			r.unindex(cursor)
+			result = append(result, cursor)
+		}
+	}
+
+	if len(result) > 0 {
+		r.schedule()
+	}
+
+	return result
+}
+
+// unindex drops the item from the index. The memory lock must be held.
+func (r *RepositoryMemory[T]) unindex(item T) {
+	if r.key != nil && r.index[r.key(item)] == item.PersistenceId() {
+		delete(r.index, r.key(item))
+	}
+}
+
+// Flush writes the current items to the file, without waiting for the writer.
+// A journal already holds every change, so nothing is written to it.
+func (r *RepositoryMemory[T]) Flush() error {
+	if r.journal != nil {
+		return nil
+	}
+
+	r.muFile.Lock()
+	defer r.muFile.Unlock()
+
+	r.muMemory.RLock()
+	values := r.collection.Values()
+	r.muMemory.RUnlock()
+
+	return r.file.Write(values)
+}
+
+// Close stops the writer once the pending changes are written and returns
+// the error of that last write, if any.
+func (r *RepositoryMemory[T]) Close() error {
+	r.onceClose.Do(func() {
+		close(r.close)
+		close(r.stop)
+
+		err := <-r.done
+		if r.journal != nil {
+			err = errors.Join(err, r.journal.Close())
+		}
+		r.done <- err
+	})
+	err := <-r.done
+	r.done <- err
+	return err
+}
+
+func (r *RepositoryMemory[T]) schedule() {
+	if r.journal != nil {
+		return
+	}
+
+	select {
+	case r.dirty <- struct{}{}:
+	default:
+	}
+}
+
+func (r *RepositoryMemory[T]) writer() {
+	for {
+		select {
+		case <-r.dirty:
+			select {
+			case <-time.After(writeDelay):
+			case <-r.stop:
+			}
+			if err := r.Flush(); err != nil {
+				log.Error(err)
+			}
+		case <-r.stop:
+			r.done <- r.Flush()
+			return
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package account
+
+import (
+	"time"
+
+	topic_repository "github.com/Rafael24595/go-api-render/src/commons/system/topic/repository"
+	account_domain "github.com/Rafael24595/go-api-render/src/domain/account"
+
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository"
+	"github.com/Rafael24595/go-collections/collection"
+)
+
+const NameMemory = "account_memory"
+
+// RepositoryMemory keeps the accounts in memory on top of the shared memory
+// repository, which writes them to the file.
+type RepositoryMemory struct {
+	*repository.RepositoryMemory[account_domain.Account]
+}
+
+func InitializeRepositoryMemory(impl collection.IDictionary[string, account_domain.Account], file core_repository.IFileManager[account_domain.Account]) (*RepositoryMemory, error) {
+	memory, err := repository.NewRepositoryMemory(NameMemory, topic_repository.TOPIC_ACCOUNT.ActionReload(), impl, file)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryMemory{
+		RepositoryMemory: memory,
+	}, nil
+}
+
+func (r *RepositoryMemory) FindByOwner(owner string) (*account_domain.Account, bool) {
+	return r.FindOne(func(account account_domain.Account) bool {
+		return account.Owner == owner
+	})
+}
+
+func (r *RepositoryMemory) FindByIdentity(issuer, subject string) (*account_domain.Account, bool) {
+	return r.FindOne(func(account account_domain.Account) bool {
+		return account.IsLinked(issuer, subject)
+	})
+}
+
+func (r *RepositoryMemory) Resolve(owner string, account *account_domain.Account) *account_domain.Account {
+	return r.Store(account, func(account *account_domain.Account, key string) {
+		if account.Id == "" {
+			account.Id = key
+		}
+
+		account.Owner = owner
+
+		now := time.Now().UnixMilli()
+
+		if account.Timestamp == 0 {
+			account.Timestamp = now
+		}
+
+		account.Modified = now
+	})
+}
+
+func (r *RepositoryMemory) Delete(account *account_domain.Account) *account_domain.Account {
+	cursor, _ := r.Remove(account.Id)
+	return cursor
+}
@@ -0,0 +1,190 @@
+package audit
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	core_system "github.com/Rafael24595/go-api-core/src/commons/system"
+	topic_repository "github.com/Rafael24595/go-api-render/src/commons/system/topic/repository"
+	audit_domain "github.com/Rafael24595/go-api-render/src/domain/audit"
+	render_repository "github.com/Rafael24595/go-api-render/src/infrastructure/repository"
+
+	"github.com/Rafael24595/go-api-core/src/commons/system/topic"
+	"github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	"github.com/Rafael24595/go-collections/collection"
+	"github.com/Rafael24595/go-log/log"
+	"github.com/google/uuid"
+)
+
+const NameMemory = "audit_memory"
+
+// pruneInterval is how often the events beyond the retention are pruned.
+const pruneInterval = time.Hour
+
+// RepositoryMemory keeps the events in memory and appends each one to the
+// journal as it is inserted, in order. The events beyond the retention are
+// the only ones ever removed.
+type RepositoryMemory struct {
+	once       sync.Once
+	muMemory   sync.RWMutex
+	collection collection.IDictionary[string, audit_domain.Event]
+	file       render_repository.IJournalFile[audit_domain.Event]
+	retention  configuration.Audit
+	pruned     time.Time
+	close      chan bool
+}
+
+func InitializeRepositoryMemory(impl collection.IDictionary[string, audit_domain.Event], file render_repository.IJournalFile[audit_domain.Event], retention configuration.Audit) (*RepositoryMemory, error) {
+	events, err := file.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	instance := &RepositoryMemory{
+		collection: impl.Merge(collection.DictionaryFromMap(events)),
+		file:       file,
+		retention:  retention,
+		close:      make(chan bool),
+	}
+
+	instance.prune()
+
+	go instance.watch()
+
+	return instance, nil
+}
+
+func (r *RepositoryMemory) watch() {
+	r.once.Do(func() {
+		conf := configuration.Instance()
+		if !conf.Snapshot().Enable {
+			return
+		}
+
+		hub := make(chan core_system.SystemEvent, 1)
+		defer close(hub)
+
+		topics := []topic.TopicAction{
+			topic_repository.TOPIC_AUDIT.ActionReload(),
+		}
+
+		conf.EventHub.Subcribe(repository.RepositoryListener, hub, topics...)
+		defer conf.EventHub.Unsubcribe(repository.RepositoryListener, topics...)
+
+		for {
+			select {
+			case <-r.close:
+				log.Customf(repository.RepositoryCategory, "Watcher stopped: local close signal received.")
+				return
+			case <-hub:
+				if err := r.read(); err != nil {
+					log.Custome(repository.RepositoryCategory, err)
+					return
+				}
+				log.Customf(repository.RepositoryCategory, "The repository %q has been reloaded.", NameMemory)
+			case <-conf.Signal.Done():
+				log.Customf(repository.RepositoryCategory, "Watcher stopped: global shutdown signal received.")
+				return
+			}
+		}
+	})
+}
+
+func (r *RepositoryMemory) read() error {
+	events, err := r.file.Read()
+	if err != nil {
+		return err
+	}
+
+	r.muMemory.Lock()
+	defer r.muMemory.Unlock()
+
+	r.collection = collection.DictionaryFromMap(events)
+	return nil
+}
+
+func (r *RepositoryMemory) FindAll() []audit_domain.Event {
+	r.muMemory.RLock()
+	defer r.muMemory.RUnlock()
+	return r.collection.Values()
+}
+
+func (r *RepositoryMemory) Insert(event *audit_domain.Event) *audit_domain.Event {
+	r.muMemory.Lock()
+	defer r.muMemory.Unlock()
+
+	key := uuid.New().String()
+	for r.collection.Exists(key) {
+		key = uuid.New().String()
+	}
+
+	event.Id = key
+
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixMilli()
+	}
+
+	r.collection.Put(event.Id, *event)
+
+	if err := r.file.Put(*event); err != nil {
+		log.Error(err)
+	}
+
+	if time.Since(r.pruned) >= pruneInterval {
+		r.prune()
+	}
+
+	return event
+}
+
+// Close releases the journal.
+func (r *RepositoryMemory) Close() error {
+	close(r.close)
+	return r.file.Close()
+}
+
+// prune removes the events older than the retention age and the oldest ones
+// beyond the retention limit. The memory lock must be held, or the repository
+// not shared yet.
+func (r *RepositoryMemory) prune() {
+	r.pruned = time.Now()
+
+	events := r.collection.Values()
+	slices.SortFunc(events, func(a, b audit_domain.Event) int {
+		if a.Timestamp < b.Timestamp {
+			return -1
+		}
+		if a.Timestamp > b.Timestamp {
+			return 1
+		}
+		return 0
+	})
+
+	expired := 0
+	if r.retention.MaxAge > 0 {
+		limit := time.Now().Add(-r.retention.MaxAge).UnixMilli()
+		for expired < len(events) && events[expired].Timestamp < limit {
+			expired++
+		}
+	}
+
+	if r.retention.Limit > 0 && len(events)-expired > r.retention.Limit {
+		expired = len(events) - r.retention.Limit
+	}
+
+	if expired == 0 {
+		return
+	}
+
+	ids := make([]string, expired)
+	for i, v := range events[:expired] {
+		ids[i] = v.Id
+		r.collection.Remove(v.Id)
+	}
+
+	if err := r.file.Remove(ids...); err != nil {
+		log.Error(err)
+	}
+}
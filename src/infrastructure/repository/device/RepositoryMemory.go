@@ -0,0 +1,61 @@
+package device
+
+import (
+	"time"
+
+	topic_repository "github.com/Rafael24595/go-api-render/src/commons/system/topic/repository"
+	device_domain "github.com/Rafael24595/go-api-render/src/domain/device"
+
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository"
+	"github.com/Rafael24595/go-collections/collection"
+)
+
+const NameMemory = "device_memory"
+
+// RepositoryMemory keeps the devices in memory on top of the shared memory
+// repository, which writes them to the file.
+type RepositoryMemory struct {
+	*repository.RepositoryMemory[device_domain.Device]
+}
+
+func InitializeRepositoryMemory(impl collection.IDictionary[string, device_domain.Device], file core_repository.IFileManager[device_domain.Device]) (*RepositoryMemory, error) {
+	memory, err := repository.NewRepositoryMemory(NameMemory, topic_repository.TOPIC_DEVICE.ActionReload(), impl, file)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryMemory{
+		RepositoryMemory: memory,
+	}, nil
+}
+
+func (r *RepositoryMemory) FindByOwner(owner string) []device_domain.Device {
+	return r.FindMany(func(device device_domain.Device) bool {
+		return device.Owner == owner
+	})
+}
+
+func (r *RepositoryMemory) Resolve(owner string, device *device_domain.Device) *device_domain.Device {
+	return r.Store(device, func(device *device_domain.Device, key string) {
+		if device.Id == "" {
+			device.Id = key
+		}
+
+		device.Owner = owner
+
+		now := time.Now().UnixMilli()
+
+		if device.Timestamp == 0 {
+			device.Timestamp = now
+		}
+
+		if device.LastSeen == 0 {
+			device.LastSeen = now
+		}
+	})
+}
+
+func (r *RepositoryMemory) Delete(device *device_domain.Device) *device_domain.Device {
+	cursor, _ := r.Remove(device.Id)
+	return cursor
+}
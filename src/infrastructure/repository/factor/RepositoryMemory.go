@@ -0,0 +1,55 @@
+package factor
+
+import (
+	"time"
+
+	topic_repository "github.com/Rafael24595/go-api-render/src/commons/system/topic/repository"
+	factor_domain "github.com/Rafael24595/go-api-render/src/domain/factor"
+
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository"
+	"github.com/Rafael24595/go-collections/collection"
+)
+
+const NameMemory = "factor_memory"
+
+// RepositoryMemory keeps the second factors in memory on top of the shared memory
+// repository, which writes them to the file.
+type RepositoryMemory struct {
+	*repository.RepositoryMemory[factor_domain.Factor]
+}
+
+func InitializeRepositoryMemory(impl collection.IDictionary[string, factor_domain.Factor], file core_repository.IFileManager[factor_domain.Factor]) (*RepositoryMemory, error) {
+	memory, err := repository.NewRepositoryMemory(NameMemory, topic_repository.TOPIC_FACTOR.ActionReload(), impl, file)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryMemory{
+		RepositoryMemory: memory,
+	}, nil
+}
+
+func (r *RepositoryMemory) FindByOwner(owner string) (*factor_domain.Factor, bool) {
+	return r.FindOne(func(factor factor_domain.Factor) bool {
+		return factor.Owner == owner
+	})
+}
+
+func (r *RepositoryMemory) Resolve(owner string, factor *factor_domain.Factor) *factor_domain.Factor {
+	return r.Store(factor, func(factor *factor_domain.Factor, key string) {
+		if factor.Id == "" {
+			factor.Id = key
+		}
+
+		factor.Owner = owner
+
+		if factor.Timestamp == 0 {
+			factor.Timestamp = time.Now().UnixMilli()
+		}
+	})
+}
+
+func (r *RepositoryMemory) Delete(factor *factor_domain.Factor) *factor_domain.Factor {
+	cursor, _ := r.Remove(factor.Id)
+	return cursor
+}
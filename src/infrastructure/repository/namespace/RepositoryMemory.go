@@ -0,0 +1,59 @@
+package namespace
+
+import (
+	"time"
+
+	topic_repository "github.com/Rafael24595/go-api-render/src/commons/system/topic/repository"
+	namespace_domain "github.com/Rafael24595/go-api-render/src/domain/namespace"
+
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository"
+	"github.com/Rafael24595/go-collections/collection"
+)
+
+const NameMemory = "namespace_memory"
+
+// RepositoryMemory keeps the namespaces in memory on top of the shared memory
+// repository, which writes them to the file.
+type RepositoryMemory struct {
+	*repository.RepositoryMemory[namespace_domain.Namespace]
+}
+
+func InitializeRepositoryMemory(impl collection.IDictionary[string, namespace_domain.Namespace], file core_repository.IFileManager[namespace_domain.Namespace]) (*RepositoryMemory, error) {
+	memory, err := repository.NewRepositoryMemory(NameMemory, topic_repository.TOPIC_NAMESPACE.ActionReload(), impl, file)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryMemory{
+		RepositoryMemory: memory,
+	}, nil
+}
+
+func (r *RepositoryMemory) FindByName(name string) (*namespace_domain.Namespace, bool) {
+	return r.FindOne(func(namespace namespace_domain.Namespace) bool {
+		return namespace.Name == name
+	})
+}
+
+func (r *RepositoryMemory) Resolve(owner string, namespace *namespace_domain.Namespace) *namespace_domain.Namespace {
+	return r.Store(namespace, func(namespace *namespace_domain.Namespace, key string) {
+		if namespace.Id == "" {
+			namespace.Id = key
+		}
+
+		namespace.Owner = owner
+
+		now := time.Now().UnixMilli()
+
+		if namespace.Timestamp == 0 {
+			namespace.Timestamp = now
+		}
+
+		namespace.Modified = now
+	})
+}
+
+func (r *RepositoryMemory) Delete(namespace *namespace_domain.Namespace) *namespace_domain.Namespace {
+	cursor, _ := r.Remove(namespace.Id)
+	return cursor
+}
@@ -0,0 +1,54 @@
+package revision
+
+import (
+	"time"
+
+	topic_repository "github.com/Rafael24595/go-api-render/src/commons/system/topic/repository"
+	revision_domain "github.com/Rafael24595/go-api-render/src/domain/revision"
+
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository"
+	"github.com/Rafael24595/go-collections/collection"
+)
+
+const NameMemory = "revision_memory"
+
+// RepositoryMemory keeps the revisions in memory on top of the shared memory
+// repository, which writes them to the file.
+type RepositoryMemory struct {
+	*repository.RepositoryMemory[revision_domain.Revision]
+}
+
+func InitializeRepositoryMemory(impl collection.IDictionary[string, revision_domain.Revision], file core_repository.IFileManager[revision_domain.Revision]) (*RepositoryMemory, error) {
+	memory, err := repository.NewRepositoryMemory(NameMemory, topic_repository.TOPIC_WEB_REVISION.ActionReload(), impl, file)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryMemory{
+		RepositoryMemory: memory,
+	}, nil
+}
+
+func (r *RepositoryMemory) FindByOwner(owner string) []revision_domain.Revision {
+	return r.FindMany(func(revision revision_domain.Revision) bool {
+		return revision.Owner == owner
+	})
+}
+
+func (r *RepositoryMemory) Insert(revision *revision_domain.Revision) *revision_domain.Revision {
+	return r.Store(revision, func(revision *revision_domain.Revision, key string) {
+		revision.Id = key
+
+		if revision.Timestamp == 0 {
+			revision.Timestamp = time.Now().UnixMilli()
+		}
+	})
+}
+
+func (r *RepositoryMemory) Delete(revisions ...revision_domain.Revision) []revision_domain.Revision {
+	ids := make([]string, len(revisions))
+	for i, v := range revisions {
+		ids[i] = v.Id
+	}
+	return r.RemoveMany(ids...)
+}
@@ -0,0 +1,59 @@
+package role
+
+import (
+	"time"
+
+	topic_repository "github.com/Rafael24595/go-api-render/src/commons/system/topic/repository"
+	role_domain "github.com/Rafael24595/go-api-render/src/domain/role"
+
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository"
+	"github.com/Rafael24595/go-collections/collection"
+)
+
+const NameMemory = "role_memory"
+
+// RepositoryMemory keeps the roles in memory on top of the shared memory
+// repository, which writes them to the file.
+type RepositoryMemory struct {
+	*repository.RepositoryMemory[role_domain.Role]
+}
+
+func InitializeRepositoryMemory(impl collection.IDictionary[string, role_domain.Role], file core_repository.IFileManager[role_domain.Role]) (*RepositoryMemory, error) {
+	memory, err := repository.NewRepositoryMemory(NameMemory, topic_repository.TOPIC_ROLE.ActionReload(), impl, file)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryMemory{
+		RepositoryMemory: memory,
+	}, nil
+}
+
+func (r *RepositoryMemory) FindByName(name string) (*role_domain.Role, bool) {
+	return r.FindOne(func(role role_domain.Role) bool {
+		return role.Name == name
+	})
+}
+
+func (r *RepositoryMemory) Resolve(owner string, role *role_domain.Role) *role_domain.Role {
+	return r.Store(role, func(role *role_domain.Role, key string) {
+		if role.Id == "" {
+			role.Id = key
+		}
+
+		role.Owner = owner
+
+		now := time.Now().UnixMilli()
+
+		if role.Timestamp == 0 {
+			role.Timestamp = now
+		}
+
+		role.Modified = now
+	})
+}
+
+func (r *RepositoryMemory) Delete(role *role_domain.Role) *role_domain.Role {
+	cursor, _ := r.Remove(role.Id)
+	return cursor
+}
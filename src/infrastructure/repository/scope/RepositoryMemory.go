@@ -0,0 +1,59 @@
+package scope
+
+import (
+	"time"
+
+	topic_repository "github.com/Rafael24595/go-api-render/src/commons/system/topic/repository"
+	scope_domain "github.com/Rafael24595/go-api-render/src/domain/scope"
+
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository"
+	"github.com/Rafael24595/go-collections/collection"
+)
+
+const NameMemory = "scope_memory"
+
+// RepositoryMemory keeps the token policies in memory on top of the shared memory
+// repository, which writes them to the file.
+type RepositoryMemory struct {
+	*repository.RepositoryMemory[scope_domain.Policy]
+}
+
+func InitializeRepositoryMemory(impl collection.IDictionary[string, scope_domain.Policy], file core_repository.IFileManager[scope_domain.Policy]) (*RepositoryMemory, error) {
+	memory, err := repository.NewRepositoryMemory(NameMemory, topic_repository.TOPIC_SCOPE.ActionReload(), impl, file)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryMemory{
+		RepositoryMemory: memory,
+	}, nil
+}
+
+func (r *RepositoryMemory) FindByToken(token string) (*scope_domain.Policy, bool) {
+	return r.FindOne(func(policy scope_domain.Policy) bool {
+		return policy.Token == token
+	})
+}
+
+func (r *RepositoryMemory) Resolve(owner string, policy *scope_domain.Policy) *scope_domain.Policy {
+	return r.Store(policy, func(policy *scope_domain.Policy, key string) {
+		if policy.Id == "" {
+			policy.Id = key
+		}
+
+		policy.Owner = owner
+
+		now := time.Now().UnixMilli()
+
+		if policy.Timestamp == 0 {
+			policy.Timestamp = now
+		}
+
+		policy.Modified = now
+	})
+}
+
+func (r *RepositoryMemory) Delete(policy *scope_domain.Policy) *scope_domain.Policy {
+	cursor, _ := r.Remove(policy.Id)
+	return cursor
+}
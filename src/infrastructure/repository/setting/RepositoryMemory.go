@@ -0,0 +1,42 @@
+package setting
+
+import (
+	"time"
+
+	topic_repository "github.com/Rafael24595/go-api-render/src/commons/system/topic/repository"
+	setting_domain "github.com/Rafael24595/go-api-render/src/domain/setting"
+
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository"
+	"github.com/Rafael24595/go-collections/collection"
+)
+
+const NameMemory = "setting_memory"
+
+// RepositoryMemory keeps the settings in memory on top of the shared memory
+// repository, which writes them to the file.
+type RepositoryMemory struct {
+	*repository.RepositoryMemory[setting_domain.Setting]
+}
+
+func InitializeRepositoryMemory(impl collection.IDictionary[string, setting_domain.Setting], file core_repository.IFileManager[setting_domain.Setting]) (*RepositoryMemory, error) {
+	memory, err := repository.NewRepositoryMemory(NameMemory, topic_repository.TOPIC_SETTING.ActionReload(), impl, file)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryMemory{
+		RepositoryMemory: memory,
+	}, nil
+}
+
+func (r *RepositoryMemory) Resolve(owner string, setting *setting_domain.Setting) *setting_domain.Setting {
+	return r.Store(setting, func(setting *setting_domain.Setting, _ string) {
+		setting.Owner = owner
+		setting.Modified = time.Now().UnixMilli()
+	})
+}
+
+func (r *RepositoryMemory) Delete(setting *setting_domain.Setting) *setting_domain.Setting {
+	cursor, _ := r.Remove(setting.Id)
+	return cursor
+}
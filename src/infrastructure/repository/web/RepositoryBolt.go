@@ -0,0 +1,224 @@
+package web
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	web_domain "github.com/Rafael24595/go-api-render/src/domain/web"
+	"github.com/Rafael24595/go-log/log"
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+const NameBolt = "web_bolt"
+
+var (
+	bucketWebData = []byte("web_data")
+	bucketOwner   = []byte("web_data_owner")
+)
+
+// RepositoryBolt keeps the web data in an embedded key-value database, so it
+// is neither held in memory nor rewritten whole on every change. Every change
+// runs in its own transaction, which also keeps the owner index in step.
+type RepositoryBolt struct {
+	db *bolt.DB
+}
+
+func InitializeRepositoryBolt(path string) (*RepositoryBolt, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(bucketWebData); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(bucketOwner)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &RepositoryBolt{
+		db: db,
+	}, nil
+}
+
+func (r *RepositoryBolt) Find(id string) (*web_domain.WebData, bool) {
+	var webData *web_domain.WebData
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		webData, err = r.get(tx, id)
+		return err
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return r.found(webData)
+}
+
+func (r *RepositoryBolt) FindByOwner(owner string) (*web_domain.WebData, bool) {
+	var webData *web_domain.WebData
+	err := r.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(bucketOwner).Get([]byte(owner))
+		if id == nil {
+			return nil
+		}
+		var err error
+		webData, err = r.get(tx, string(id))
+		return err
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return r.found(webData)
+}
+
+// Resolve stores the web data. The error of the transaction is returned, so a
+// change that could not be written is never taken for a stored one.
+func (r *RepositoryBolt) Resolve(owner string, webData *web_domain.WebData) (*web_domain.WebData, error) {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketWebData)
+
+		if webData.Id == "" {
+			key := uuid.New().String()
+			for data.Get([]byte(key)) != nil {
+				key = uuid.New().String()
+			}
+			webData.Id = key
+		}
+
+		now := time.Now().UnixMilli()
+
+		if webData.Timestamp == 0 {
+			webData.Timestamp = now
+		}
+
+		webData.Modified = now
+		webData.Owner = owner
+
+		return r.put(tx, webData)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return webData, nil
+}
+
+func (r *RepositoryBolt) Delete(webData *web_domain.WebData) (*web_domain.WebData, error) {
+	var cursor *web_domain.WebData
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		current, err := r.get(tx, webData.Id)
+		if err != nil || current == nil {
+			return err
+		}
+
+		cursor = current
+
+		owners := tx.Bucket(bucketOwner)
+		if string(owners.Get([]byte(current.Owner))) == current.Id {
+			if err := owners.Delete([]byte(current.Owner)); err != nil {
+				return err
+			}
+		}
+
+		return tx.Bucket(bucketWebData).Delete([]byte(current.Id))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cursor == nil {
+		cursor = &web_domain.WebData{}
+	}
+	return cursor, nil
+}
+
+// Import stores the given web data as it is, keeping its identifiers and
+// dates, in a single transaction. The data of owners already stored is
+// skipped.
+func (r *RepositoryBolt) Import(items []web_domain.WebData) (int, int, error) {
+	imported, skipped := 0, 0
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		imported, skipped = 0, 0
+		owners := tx.Bucket(bucketOwner)
+		for i := range items {
+			if items[i].Id == "" || owners.Get([]byte(items[i].Owner)) != nil {
+				skipped++
+				continue
+			}
+			if err := r.put(tx, &items[i]); err != nil {
+				return err
+			}
+			imported++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return imported, skipped, nil
+}
+
+func (r *RepositoryBolt) Flush() error {
+	return r.db.Sync()
+}
+
+func (r *RepositoryBolt) Close() error {
+	return r.db.Close()
+}
+
+func (r *RepositoryBolt) get(tx *bolt.Tx, id string) (*web_domain.WebData, error) {
+	raw := tx.Bucket(bucketWebData).Get([]byte(id))
+	if raw == nil {
+		return nil, nil
+	}
+
+	var webData web_domain.WebData
+	if err := json.Unmarshal(raw, &webData); err != nil {
+		return nil, err
+	}
+
+	return &webData, nil
+}
+
+func (r *RepositoryBolt) put(tx *bolt.Tx, webData *web_domain.WebData) error {
+	encoded, err := json.Marshal(webData)
+	if err != nil {
+		return err
+	}
+
+	owners := tx.Bucket(bucketOwner)
+
+	previous, err := r.get(tx, webData.Id)
+	if err != nil {
+		return err
+	}
+
+	if previous != nil && previous.Owner != webData.Owner && string(owners.Get([]byte(previous.Owner))) == webData.Id {
+		if err := owners.Delete([]byte(previous.Owner)); err != nil {
+			return err
+		}
+	}
+
+	if err := owners.Put([]byte(webData.Owner), []byte(webData.Id)); err != nil {
+		return err
+	}
+
+	return tx.Bucket(bucketWebData).Put([]byte(webData.Id), encoded)
+}
+
+func (r *RepositoryBolt) found(webData *web_domain.WebData) (*web_domain.WebData, bool) {
+	if webData == nil {
+		return &web_domain.WebData{}, false
+	}
+	return webData, true
+}
@@ -1,162 +1,63 @@
 package web
 
 import (
-	"sync"
 	"time"
 
-	core_system "github.com/Rafael24595/go-api-core/src/commons/system"
 	topic_repository "github.com/Rafael24595/go-api-render/src/commons/system/topic/repository"
 	web_domain "github.com/Rafael24595/go-api-render/src/domain/web"
 
-	"github.com/Rafael24595/go-api-core/src/commons/system/topic"
-	"github.com/Rafael24595/go-api-core/src/infrastructure/repository"
-	"github.com/Rafael24595/go-api-render/src/commons/configuration"
+	core_repository "github.com/Rafael24595/go-api-core/src/infrastructure/repository"
+	"github.com/Rafael24595/go-api-render/src/infrastructure/repository"
 	"github.com/Rafael24595/go-collections/collection"
-	"github.com/Rafael24595/go-log/log"
-	"github.com/google/uuid"
 )
 
 const NameMemory = "web_memory"
 
+// RepositoryMemory keeps the web data in memory, indexed by owner, on top of
+// the shared memory repository. When the file is a journal every change is
+// appended to it before it is acknowledged.
 type RepositoryMemory struct {
-	once       sync.Once
-	muMemory   sync.RWMutex
-	muFile     sync.RWMutex
-	collection collection.IDictionary[string, web_domain.WebData]
-	file       repository.IFileManager[web_domain.WebData]
-	close      chan bool
+	*repository.RepositoryMemory[web_domain.WebData]
 }
 
-func InitializeRepositoryMemory(impl collection.IDictionary[string, web_domain.WebData], file repository.IFileManager[web_domain.WebData]) (*RepositoryMemory, error) {
-	requests, err := file.Read()
+func InitializeRepositoryMemory(impl collection.IDictionary[string, web_domain.WebData], file core_repository.IFileManager[web_domain.WebData]) (*RepositoryMemory, error) {
+	memory, err := repository.NewRepositoryMemory(NameMemory, topic_repository.TOPIC_WEB_DATA.ActionReload(), impl, file)
 	if err != nil {
 		return nil, err
 	}
+	return &RepositoryMemory{
+		RepositoryMemory: memory.IndexBy(func(webData web_domain.WebData) string {
+			return webData.Owner
+		}),
+	}, nil
+}
 
-	instance := &RepositoryMemory{
-		collection: impl.Merge(collection.DictionaryFromMap(requests)),
-		file:       file,
-	}
-
-	go instance.watch()
-
-	return instance, nil
+func (r *RepositoryMemory) FindByOwner(owner string) (*web_domain.WebData, bool) {
+	return r.FindIndexed(owner)
 }
 
-func (r *RepositoryMemory) watch() {
-	r.once.Do(func() {
-		conf := configuration.Instance()
-		if !conf.Snapshot().Enable {
-			return
+func (r *RepositoryMemory) Resolve(owner string, webData *web_domain.WebData) (*web_domain.WebData, error) {
+	return r.Save(webData, func(webData *web_domain.WebData, key string) {
+		if webData.Id == "" {
+			webData.Id = key
 		}
 
-		hub := make(chan core_system.SystemEvent, 1)
-		defer close(hub)
+		webData.Owner = owner
 
-		topics := []topic.TopicAction{
-			topic_repository.TOPIC_WEB_DATA.ActionReload(),
-		}
-
-		conf.EventHub.Subcribe(repository.RepositoryListener, hub, topics...)
-		defer conf.EventHub.Unsubcribe(repository.RepositoryListener, topics...)
+		now := time.Now().UnixMilli()
 
-		for {
-			select {
-			case <-r.close:
-				log.Customf(repository.RepositoryCategory, "Watcher stopped: local close signal received.")
-				return
-			case <-hub:
-				if err := r.read(); err != nil {
-					log.Custome(repository.RepositoryCategory, err)
-					return
-				}
-				log.Customf(repository.RepositoryCategory, "The repository %q has been reloaded.", NameMemory)
-			case <-conf.Signal.Done():
-				log.Customf(repository.RepositoryCategory, "Watcher stopped: global shutdown signal received.")
-				return
-			}
+		if webData.Timestamp == 0 {
+			webData.Timestamp = now
 		}
-	})
-}
-
-func (r *RepositoryMemory) read() error {
-	requests, err := r.file.Read()
-	if err != nil {
-		return err
-	}
-
-	r.collection = collection.DictionaryFromMap(requests)
-	return nil
-}
-
-func (r *RepositoryMemory) Find(id string) (*web_domain.WebData, bool) {
-	r.muMemory.RLock()
-	defer r.muMemory.RUnlock()
-	data, ok := r.collection.Get(id)
-	return &data, ok
-}
 
-func (r *RepositoryMemory) FindByOwner(owner string) (*web_domain.WebData, bool) {
-	r.muMemory.RLock()
-	defer r.muMemory.RUnlock()
-	data, ok := r.collection.FindOne(func(s string, w web_domain.WebData) bool {
-		return w.Owner == owner
+		webData.Modified = now
 	})
-	return &data, ok
-}
-
-func (r *RepositoryMemory) Resolve(owner string, webData *web_domain.WebData) *web_domain.WebData {
-	r.muMemory.Lock()
-	defer r.muMemory.Unlock()
-
-	if webData.Id != "" {
-		return r.insert(owner, webData)
-	}
-
-	key := uuid.New().String()
-	if r.collection.Exists(key) {
-		return r.Resolve(owner, webData)
-	}
-
-	webData.Id = key
-
-	return r.insert(owner, webData)
-}
-
-func (r *RepositoryMemory) insert(owner string, webData *web_domain.WebData) *web_domain.WebData {
-	webData.Owner = owner
-
-	now := time.Now().UnixMilli()
-
-	if webData.Timestamp == 0 {
-		webData.Timestamp = now
-	}
-
-	webData.Modified = now
-
-	r.collection.Put(webData.Id, *webData)
-
-	go r.write(r.collection)
-
-	return webData
-}
-
-func (r *RepositoryMemory) Delete(webData *web_domain.WebData) *web_domain.WebData {
-	r.muMemory.Lock()
-	defer r.muMemory.Unlock()
-
-	cursor, _ := r.collection.Remove(webData.Id)
-	go r.write(r.collection)
-
-	return &cursor
 }
 
-func (r *RepositoryMemory) write(snapshot collection.IDictionary[string, web_domain.WebData]) {
-	r.muFile.Lock()
-	defer r.muFile.Unlock()
-
-	err := r.file.Write(snapshot.Values())
+func (r *RepositoryMemory) Delete(webData *web_domain.WebData) (*web_domain.WebData, error) {
+	cursor, _, err := r.Discard(webData.Id)
 	if err != nil {
-		log.Error(err)
+		return nil, err
 	}
+	return cursor, nil
 }